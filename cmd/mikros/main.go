@@ -16,7 +16,6 @@ func main() {
 		ctx     = context.Background()
 		options = []fang.Option{
 			fang.WithoutVersion(),
-			fang.WithoutCompletions(),
 		}
 	)
 
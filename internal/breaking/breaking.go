@@ -0,0 +1,159 @@
+package breaking
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mikros-dev/mikros-cli/internal/git"
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+// Kind is the kind of breaking change.
+type Kind string
+
+// Supported kinds of breaking changes.
+const (
+	KindMessageRemoved      Kind = "MESSAGE_REMOVED"
+	KindFieldRemoved        Kind = "FIELD_REMOVED"
+	KindFieldRenumbered     Kind = "FIELD_RENUMBERED"
+	KindFieldTypeChanged    Kind = "FIELD_TYPE_CHANGED"
+	KindEnumRemoved         Kind = "ENUM_REMOVED"
+	KindEnumValueRemoved    Kind = "ENUM_VALUE_REMOVED"
+	KindEnumValueRenumbered Kind = "ENUM_VALUE_RENUMBERED"
+	KindServiceRemoved      Kind = "SERVICE_REMOVED"
+	KindRPCRemoved          Kind = "RPC_REMOVED"
+	KindRPCTypeChanged      Kind = "RPC_TYPE_CHANGED"
+	KindHTTPRuleChanged     Kind = "HTTP_RULE_CHANGED"
+	KindReservedRemoved     Kind = "RESERVED_REMOVED"
+	KindReservedReused      Kind = "RESERVED_REUSED"
+)
+
+// Options holds options for checking breaking changes.
+type Options struct {
+	// Against is the git reference whose files are compared with the ones
+	// from the working tree.
+	Against string
+
+	// Path is the directory containing the protobuf files, used as the proto
+	// root when resolving imports.
+	Path string
+}
+
+// Result is the result of a breaking changes check.
+type Result struct {
+	Against string    `json:"against"`
+	Path    string    `json:"path"`
+	Files   int       `json:"files"`
+	Changes []*Change `json:"changes"`
+}
+
+// Change is a wire-incompatible change found in a file.
+type Change struct {
+	Kind Kind `json:"kind"`
+
+	// File is the file, relative to the proto root, where the change was
+	// found. For removed elements, it is the file where they were declared.
+	File string `json:"file"`
+
+	// Element is the fully qualified name of the changed element.
+	Element string `json:"element"`
+
+	Message string `json:"message"`
+}
+
+// Check compares the protobuf files of the working tree with their versions
+// at a git reference and returns the breaking changes found.
+func Check(ctx context.Context, options *Options) (*Result, error) {
+	path, err := filepath.Abs(options.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := git.TopLevel(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Symbolic links, like temporary directories on some systems, must be
+	// resolved for the path to be relative to the repository root.
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	relPath, err := filepath.Rel(root, realPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpPath, err := os.MkdirTemp("", "mikros-breaking-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpPath)
+	}()
+
+	if err := git.Export(ctx, root, options.Against, filepath.ToSlash(relPath), tmpPath); err != nil {
+		return nil, err
+	}
+
+	previous, err := loadFiles(filepath.Join(tmpPath, relPath))
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := loadFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Against: options.Against,
+		Path:    options.Path,
+		Files:   len(current.Files()),
+		Changes: Compare(previous, current),
+	}, nil
+}
+
+// loadFiles loads every protobuf file below root, using it as import path.
+func loadFiles(root string) (*protobuf.Registry, error) {
+	registry := protobuf.NewRegistry(root)
+
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return registry, nil
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || filepath.Ext(path) != ".proto" {
+			return nil
+		}
+
+		_, err = registry.Load(path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+func sortChanges(changes []*Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return changes[i].File < changes[j].File
+		}
+
+		return changes[i].Element < changes[j].Element
+	})
+}
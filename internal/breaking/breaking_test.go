@@ -0,0 +1,118 @@
+package breaking
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	previous, err := loadFiles(filepath.Join("testdata", "previous"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := loadFiles(filepath.Join("testdata", "current"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range Compare(previous, current) {
+		if c.File != "acme/orders.proto" {
+			t.Errorf("%s: unexpected file %q", c.Element, c.File)
+		}
+		got = append(got, string(c.Kind)+" "+c.Element)
+	}
+
+	want := []string{
+		"ENUM_REMOVED acme.orders.Channel",
+		"MESSAGE_REMOVED acme.orders.Draft",
+		"SERVICE_REMOVED acme.orders.LegacyService",
+		"RESERVED_REMOVED acme.orders.Order",
+		"RESERVED_REMOVED acme.orders.Order",
+		"FIELD_REMOVED acme.orders.Order.discount",
+		"FIELD_TYPE_CHANGED acme.orders.Order.items",
+		"FIELD_TYPE_CHANGED acme.orders.Order.labels",
+		"RESERVED_REUSED acme.orders.Order.legacy",
+		"RESERVED_REUSED acme.orders.Order.legacy",
+		"FIELD_RENUMBERED acme.orders.Order.notes",
+		"FIELD_TYPE_CHANGED acme.orders.Order.total",
+		"RPC_REMOVED acme.orders.OrdersService.ArchiveOrder",
+		"RPC_TYPE_CHANGED acme.orders.OrdersService.CancelOrder",
+		"HTTP_RULE_CHANGED acme.orders.OrdersService.CreateOrder",
+		"HTTP_RULE_CHANGED acme.orders.OrdersService.GetOrder",
+		"RPC_TYPE_CHANGED acme.orders.OrdersService.WatchOrders",
+		"ENUM_VALUE_RENUMBERED acme.orders.Status.STATUS_CLOSED",
+		"ENUM_VALUE_REMOVED acme.orders.Status.STATUS_REFUNDED",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got changes:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	var (
+		repo     = t.TempDir()
+		filename = filepath.Join(repo, "proto", "acme", "orders.proto")
+		git      = func(args ...string) {
+			cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test",
+				"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v: %s", args, err, out)
+			}
+		}
+	)
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join("testdata", "previous", "acme", "orders.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	path := filepath.Join(repo, "proto")
+	result, err := Check(context.Background(), &Options{Against: "HEAD", Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Files != 1 || len(result.Changes) != 0 {
+		t.Errorf("unchanged: got %d files and changes %+v", result.Files, result.Changes)
+	}
+
+	// Removes Draft from the working tree.
+	content = []byte(strings.Replace(string(content), "message Draft {\n  string id = 1;\n}\n", "", 1))
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = Check(context.Background(), &Options{Against: "HEAD", Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Kind != KindMessageRemoved {
+		t.Errorf("changed: got %+v", result.Changes)
+	}
+
+	if _, err := Check(context.Background(), &Options{Against: "unknown", Path: path}); err == nil {
+		t.Error("unknown reference: expected an error")
+	}
+}
@@ -0,0 +1,292 @@
+package breaking
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+// declaration is an element along with the file declaring it.
+type declaration[T any] struct {
+	File  string
+	Value T
+}
+
+type comparison struct {
+	changes []*Change
+}
+
+// Compare returns the breaking changes found in the files of current when
+// compared with the ones of previous. Elements are matched by their fully
+// qualified names, so moving them between files is not a breaking change.
+func Compare(previous, current *protobuf.Registry) []*Change {
+	c := &comparison{}
+
+	c.compareMessages(indexMessages(previous), indexMessages(current))
+	c.compareEnums(indexEnums(previous), indexEnums(current))
+	c.compareServices(indexServices(previous), indexServices(current))
+
+	sortChanges(c.changes)
+	return c.changes
+}
+
+func (c *comparison) add(kind Kind, file, element, format string, args ...interface{}) {
+	c.changes = append(c.changes, &Change{
+		Kind:    kind,
+		File:    file,
+		Element: element,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparison) compareMessages(previous, current map[string]*declaration[*protobuf.Message]) {
+	for name, prev := range previous {
+		cur, ok := current[name]
+		if !ok {
+			c.add(KindMessageRemoved, prev.File, name, "message '%s' was removed", name)
+			continue
+		}
+
+		c.compareFields(cur.File, prev.Value, cur.Value)
+		c.compareReserved(cur.File, name, &prev.Value.Reserved, &cur.Value.Reserved)
+
+		for _, f := range cur.Value.Fields {
+			c.checkReservedReuse(cur.File, name+"."+f.Name, "field", f.Name, f.Number, &prev.Value.Reserved)
+		}
+	}
+}
+
+func (c *comparison) compareFields(file string, previous, current *protobuf.Message) {
+	for _, prev := range previous.Fields {
+		element := previous.FullName + "." + prev.Name
+
+		if cur, ok := current.Field(prev.Name); ok && cur.Number != prev.Number {
+			c.add(KindFieldRenumbered, file, element, "field '%s' changed its number from %d to %d", prev.Name, prev.Number, cur.Number)
+			continue
+		}
+
+		cur, ok := current.FieldByNumber(prev.Number)
+		if !ok {
+			if !current.Reserved.HasNumber(prev.Number) {
+				c.add(KindFieldRemoved, file, element, "field '%s' (%d) was removed without reserving its number", prev.Name, prev.Number)
+			}
+			continue
+		}
+
+		if prevType, curType := fieldType(prev), fieldType(cur); prevType != curType {
+			c.add(KindFieldTypeChanged, file, element, "field '%s' (%d) changed its type from '%s' to '%s'", prev.Name, prev.Number, prevType, curType)
+		}
+	}
+}
+
+func (c *comparison) compareEnums(previous, current map[string]*declaration[*protobuf.Enum]) {
+	for name, prev := range previous {
+		cur, ok := current[name]
+		if !ok {
+			c.add(KindEnumRemoved, prev.File, name, "enum '%s' was removed", name)
+			continue
+		}
+
+		for _, prevValue := range prev.Value.Values {
+			element := name + "." + prevValue.Name
+
+			if curValue, ok := cur.Value.Value(prevValue.Name); ok && curValue.Number != prevValue.Number {
+				c.add(KindEnumValueRenumbered, cur.File, element, "enum value '%s' changed its number from %d to %d", prevValue.Name, prevValue.Number, curValue.Number)
+				continue
+			}
+
+			if _, ok := cur.Value.ValueByNumber(prevValue.Number); !ok && !cur.Value.Reserved.HasNumber(prevValue.Number) {
+				c.add(KindEnumValueRemoved, cur.File, element, "enum value '%s' (%d) was removed without reserving its number", prevValue.Name, prevValue.Number)
+			}
+		}
+
+		c.compareReserved(cur.File, name, &prev.Value.Reserved, &cur.Value.Reserved)
+
+		for _, v := range cur.Value.Values {
+			c.checkReservedReuse(cur.File, name+"."+v.Name, "enum value", v.Name, v.Number, &prev.Value.Reserved)
+		}
+	}
+}
+
+// compareReserved checks if numbers and names reserved by previous are still
+// reserved by current.
+func (c *comparison) compareReserved(file, element string, previous, current *protobuf.Reserved) {
+	for _, rg := range previous.Ranges {
+		if !current.Covers(rg) {
+			c.add(KindReservedRemoved, file, element, "reserved range '%s' of '%s' was removed", rg, element)
+		}
+	}
+
+	for _, name := range previous.Names {
+		if !current.HasName(name) {
+			c.add(KindReservedRemoved, file, element, "reserved name '%s' of '%s' was removed", name, element)
+		}
+	}
+}
+
+// checkReservedReuse checks if a field or enum value uses a number or name
+// previously reserved.
+func (c *comparison) checkReservedReuse(file, element, what, name string, number int, previous *protobuf.Reserved) {
+	if previous.HasNumber(number) {
+		c.add(KindReservedReused, file, element, "%s '%s' uses the number %d, previously reserved", what, name, number)
+	}
+	if previous.HasName(name) {
+		c.add(KindReservedReused, file, element, "%s '%s' uses a name previously reserved", what, name)
+	}
+}
+
+func (c *comparison) compareServices(previous, current map[string]*declaration[*protobuf.Service]) {
+	for name, prev := range previous {
+		cur, ok := current[name]
+		if !ok {
+			c.add(KindServiceRemoved, prev.File, name, "service '%s' was removed", name)
+			continue
+		}
+
+		for _, prevMethod := range prev.Value.Methods {
+			element := name + "." + prevMethod.Name
+
+			curMethod, ok := findMethod(cur.Value, prevMethod.Name)
+			if !ok {
+				c.add(KindRPCRemoved, prev.File, element, "RPC '%s' was removed", prevMethod.Name)
+				continue
+			}
+
+			c.compareMethods(cur.File, element, prevMethod, curMethod)
+		}
+	}
+}
+
+func (c *comparison) compareMethods(file, element string, previous, current *protobuf.Method) {
+	if prevType, curType := typeName(previous.InputType, previous.InputName), typeName(current.InputType, current.InputName); prevType != curType {
+		c.add(KindRPCTypeChanged, file, element, "RPC '%s' changed its request from '%s' to '%s'", previous.Name, prevType, curType)
+	}
+	if prevType, curType := typeName(previous.OutputType, previous.OutputName), typeName(current.OutputType, current.OutputName); prevType != curType {
+		c.add(KindRPCTypeChanged, file, element, "RPC '%s' changed its response from '%s' to '%s'", previous.Name, prevType, curType)
+	}
+	if previous.StreamingMode() != current.StreamingMode() {
+		c.add(KindRPCTypeChanged, file, element, "RPC '%s' changed its streaming mode from %s to %s", previous.Name, previous.StreamingMode(), current.StreamingMode())
+	}
+
+	bindings := httpBindings(current.HTTP)
+	for _, binding := range httpBindings(previous.HTTP) {
+		if !slices.Contains(bindings, binding) {
+			c.add(KindHTTPRuleChanged, file, element, "HTTP binding '%s' of RPC '%s' was removed or changed", binding, previous.Name)
+		}
+	}
+}
+
+func indexMessages(r *protobuf.Registry) map[string]*declaration[*protobuf.Message] {
+	var (
+		index = make(map[string]*declaration[*protobuf.Message])
+		add   func(file string, messages []*protobuf.Message)
+	)
+
+	add = func(file string, messages []*protobuf.Message) {
+		for _, m := range messages {
+			index[m.FullName] = &declaration[*protobuf.Message]{File: file, Value: m}
+			add(file, m.Messages)
+		}
+	}
+
+	for _, p := range r.Files() {
+		add(p.Name, p.Messages)
+	}
+
+	return index
+}
+
+func indexEnums(r *protobuf.Registry) map[string]*declaration[*protobuf.Enum] {
+	var (
+		index = make(map[string]*declaration[*protobuf.Enum])
+		add   func(file string, messages []*protobuf.Message)
+	)
+
+	add = func(file string, messages []*protobuf.Message) {
+		for _, m := range messages {
+			for _, e := range m.Enums {
+				index[e.FullName] = &declaration[*protobuf.Enum]{File: file, Value: e}
+			}
+			add(file, m.Messages)
+		}
+	}
+
+	for _, p := range r.Files() {
+		for _, e := range p.Enums {
+			index[e.FullName] = &declaration[*protobuf.Enum]{File: p.Name, Value: e}
+		}
+		add(p.Name, p.Messages)
+	}
+
+	return index
+}
+
+func indexServices(r *protobuf.Registry) map[string]*declaration[*protobuf.Service] {
+	index := make(map[string]*declaration[*protobuf.Service])
+	for _, p := range r.Files() {
+		for _, svc := range p.Services {
+			name := svc.Name
+			if p.Package != "" {
+				name = p.Package + "." + svc.Name
+			}
+
+			index[name] = &declaration[*protobuf.Service]{File: p.Name, Value: svc}
+		}
+	}
+
+	return index
+}
+
+func findMethod(svc *protobuf.Service, name string) (*protobuf.Method, bool) {
+	for _, m := range svc.Methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// fieldType returns the type of a field, including its label, as it is
+// compared between versions.
+func fieldType(f *protobuf.Field) string {
+	name := f.TypeName
+	if f.TypeKind == protobuf.TypeKindUnknown || name == "" {
+		name = strings.TrimPrefix(f.Type, ".")
+	}
+
+	switch {
+	case f.IsMap():
+		return fmt.Sprintf("map<%s, %s>", f.KeyType, name)
+	case f.Repeated:
+		return "repeated " + name
+	}
+
+	return name
+}
+
+// typeName returns the resolved name of a type or, for types that could not
+// be resolved, its name as declared.
+func typeName(resolved, declared string) string {
+	if resolved != "" {
+		return resolved
+	}
+
+	return strings.TrimPrefix(declared, ".")
+}
+
+// httpBindings returns the bindings of an HTTP rule as "METHOD path".
+func httpBindings(rule *protobuf.HTTPRule) []string {
+	if rule == nil {
+		return nil
+	}
+
+	bindings := []string{rule.Method + " " + rule.Path}
+	for _, additional := range rule.AdditionalBindings {
+		bindings = append(bindings, httpBindings(additional)...)
+	}
+
+	return bindings
+}
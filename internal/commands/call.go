@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mikros-dev/mikros-cli/internal/rpc"
+)
+
+func callCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call <service>/<method>",
+		Short: "Invoke a method of a service from its protobuf API file",
+		Long: `call invokes a method of a service, building its request from JSON
+using the service protobuf API file and the files it imports, so the
+server does not need to support reflection.
+
+Methods are invoked through gRPC, except the ones of http-spec services
+(files with the openapi.metadata option), which are invoked through HTTP
+following their google.api.http bindings. Methods of gRPC services with
+HTTP bindings, like the ones served by gateways, can be invoked through
+HTTP with --transport http. Methods receiving streams accept a
+sequence, or an array, of JSON requests. Responses are printed as JSON
+as they arrive.
+
+Examples:
+ # Invoke a unary method
+ $ mikros call BillingService/GetInvoice --proto billing_api.proto \
+     --addr localhost:8080 --data '{"id": "42"}'
+
+ # Send a stream of requests read from a file
+ $ mikros call BillingService/UploadItems --proto billing_api.proto \
+     --addr localhost:8080 --data @items.json
+
+ # Invoke a method through its HTTP binding, like a gateway serves it
+ $ mikros call BillingService/GetInvoice --proto billing_api.proto \
+     --addr localhost:8080 --transport http -H "authorization: Bearer token"
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProtoMethods,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options := &rpc.Options{
+				Proto:     viper.GetString("call.proto"),
+				Method:    args[0],
+				Address:   viper.GetString("call.addr"),
+				Data:      viper.GetString("call.data"),
+				Headers:   viper.GetStringSlice("call.header"),
+				Timeout:   viper.GetDuration("call.timeout"),
+				TLS:       viper.GetBool("call.tls"),
+				Transport: rpc.Transport(viper.GetString("call.transport")),
+			}
+			if options.Proto == "" {
+				return errors.New("a protobuf API file must be given with --proto")
+			}
+			if options.Address == "" {
+				return errors.New("a server address must be given with --addr")
+			}
+
+			return rpc.Call(cmd.Context(), options, printResponse)
+		},
+	}
+
+	cmd.Flags().String("proto", "", "Sets the protobuf API file declaring the method.")
+	cmd.Flags().String("addr", "", "Sets the server address, like localhost:8080.")
+	cmd.Flags().StringP("data", "d", "", "Sets the request as JSON, or @file to read it from a file (@- for the standard input).")
+	cmd.Flags().StringArrayP("header", "H", nil, "Adds a header (or gRPC metadata) to requests, as 'name: value'.")
+	cmd.Flags().Duration("timeout", 0, "Sets how long to wait for the method to finish (default: no timeout).")
+	cmd.Flags().Bool("tls", false, "Connects to the server using TLS.")
+	cmd.Flags().String("transport", string(rpc.TransportAuto), "Sets how methods are invoked: auto, grpc or http.")
+	_ = viper.BindPFlag("call.proto", cmd.Flags().Lookup("proto"))
+	_ = viper.BindPFlag("call.addr", cmd.Flags().Lookup("addr"))
+	_ = viper.BindPFlag("call.data", cmd.Flags().Lookup("data"))
+	_ = viper.BindPFlag("call.header", cmd.Flags().Lookup("header"))
+	_ = viper.BindPFlag("call.timeout", cmd.Flags().Lookup("timeout"))
+	_ = viper.BindPFlag("call.tls", cmd.Flags().Lookup("tls"))
+	_ = viper.BindPFlag("call.transport", cmd.Flags().Lookup("transport"))
+	_ = cmd.RegisterFlagCompletionFunc("proto", completeProtoAPIFiles)
+	_ = cmd.RegisterFlagCompletionFunc("transport", cobra.FixedCompletions([]string{
+		string(rpc.TransportAuto),
+		string(rpc.TransportGRPC),
+		string(rpc.TransportHTTP),
+	}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// printResponse writes a response into the standard output as JSON, which
+// is the same for every output format.
+func printResponse(response proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
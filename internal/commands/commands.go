@@ -12,9 +12,15 @@ func EntryPoint(cfg *settings.Settings) *cobra.Command {
 	root := rootCmd()
 
 	// Configure commands
-	root.AddCommand(configCmd())
+	root.AddCommand(callCmd())
+	root.AddCommand(configCmd(cfg))
 	root.AddCommand(newCmd(cfg))
-	root.AddCommand(lintCmd())
+	root.AddCommand(lintCmd(cfg))
+	root.AddCommand(mockCmd(cfg))
+	root.AddCommand(pluginsCmd(cfg))
+	root.AddCommand(protoCmd(cfg))
+	root.AddCommand(templatesCmd(cfg))
+	root.AddCommand(upgradeCmd(cfg))
 
 	return root
 }
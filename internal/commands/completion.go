@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mikros-dev/mikros-cli/internal/git"
+	"github.com/mikros-dev/mikros-cli/internal/plugin"
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+	"github.com/mikros-dev/mikros-cli/internal/protogen"
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/pack"
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/service"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+func completeProfiles(cfg *settings.Settings) completionFunc {
+	return func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		profiles := []string{"default"}
+		for name := range cfg.Profile {
+			profiles = append(profiles, name)
+		}
+		sort.Strings(profiles)
+
+		return profiles, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeProjectKinds(cfg *settings.Settings) completionFunc {
+	return func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		kinds := slices.Clone(projectKinds)
+		packs, err := pack.List(cfg)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, p := range packs {
+			if !slices.Contains(kinds, p.Manifest.Name) {
+				kinds = append(kinds, p.Manifest.Name)
+			}
+		}
+
+		return kinds, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeServiceTypes(cfg *settings.Settings) completionFunc {
+	return func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		types, err := service.SupportedServiceTypes(cfg)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return types, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeFeatures(cfg *settings.Settings) completionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, err := plugin.GetFeaturesUINames(cfg)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		// Features are a comma separated list, so we only complete its
+		// last item.
+		var (
+			prefix   string
+			selected []string
+		)
+		if idx := strings.LastIndex(toComplete, ","); idx != -1 {
+			prefix = toComplete[:idx+1]
+			selected = strings.Split(toComplete[:idx], ",")
+		}
+
+		var features []string
+		for _, name := range names {
+			if !slices.Contains(selected, name) {
+				features = append(features, prefix+name)
+			}
+		}
+
+		return features, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// completeProtoAPIFiles completes paths to *_api.proto files, also offering
+// directories so the user can navigate through them.
+func completeProtoAPIFiles(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	dir, _ := filepath.Split(toComplete)
+
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		if entry.IsDir() {
+			paths = append(paths, dir+name+string(filepath.Separator))
+			continue
+		}
+
+		if strings.HasSuffix(name, "_api.proto") {
+			paths = append(paths, dir+name)
+		}
+	}
+
+	return paths, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeProtoServices completes services of the protobuf repository
+// containing the current directory.
+func completeProtoServices(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	repo, err := git.LoadFromCwd()
+	if err != nil || !repo.IsValidRepository() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	services, err := protogen.FindServices(repo.RootPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return services, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProtoMethods completes methods, like "Service/Method", of the
+// protobuf API file given by the --proto flag.
+func completeProtoMethods(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	filename, _ := cmd.Flags().GetString("proto")
+	if len(args) > 0 || filename == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	p, err := protobuf.Parse(filename)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var methods []string
+	for _, s := range p.Services {
+		for _, m := range s.Methods {
+			methods = append(methods, s.Name+"/"+m.Name)
+		}
+	}
+
+	return methods, cobra.ShellCompDirectiveNoFileComp
+}
@@ -2,9 +2,11 @@ package commands
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/mikros-dev/mikros-cli/internal/settings"
 )
 
-func configCmd() *cobra.Command {
+func configCmd(cfg *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Set up mikros related requirements",
@@ -17,6 +19,7 @@ inside the system.`,
 
 	cmd.AddCommand(configEditCmd())
 	cmd.AddCommand(configGenerateCmd())
+	cmd.AddCommand(configClearHistoryCmd(cfg))
 
 	return cmd
 }
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/history"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func configClearHistoryCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear-history",
+		Short: "Remove answers remembered from previous projects",
+		Long: `clear-history removes the answers that the CLI remembers from
+previous projects and uses as default values for new ones. The
+history of a single profile can be removed with the --profile
+option, otherwise everything is removed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return history.Clear(viper.GetString("config.clear-history.profile"))
+		},
+	}
+
+	cmd.Flags().String("profile", "", "Removes only the history of the given profile.")
+	_ = viper.BindPFlag("config.clear-history.profile", cmd.Flags().Lookup("profile"))
+	_ = cmd.RegisterFlagCompletionFunc("profile", completeProfiles(cfg))
+
+	return cmd
+}
@@ -7,14 +7,23 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/mikros-dev/mikros-cli/internal/lint"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/ui"
 )
 
-func lintCmd() *cobra.Command {
+func lintCmd(cfg *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "lint",
 		Short: "Analyze the code with the project's rules",
 		Long: `Runs the revive linter with the project's default configuration.
-Use it to validate style and potential issues before a commit.
+Use it to validate style and potential issues before a commit. A
+revive TOML configuration file can replace it (--config).
+
+With --proto, protobuf files are checked against the mikros
+conventions instead: package and go_package layout, service,
+file and RPC message names and openapi documentation of HTTP
+services. Rules can be configured with a TOML file (--config),
+which uses the mikros rules format instead of the revive one.
 
 It is idempotent and does not modify files by default. Use flags
 to customize paths and format.
@@ -31,19 +40,31 @@ Examples:
 
  # Run lint excluding more than one file/directory
  $ mikros lint --exclude foo/...,bar/...,file.go
+
+ # Run lint on the protobuf files of the proto directory
+ $ mikros lint --proto --path proto/...
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format := viper.GetString("lint.format")
+			if isJSONOutput() {
+				format = "json"
+			}
+
 			return lint.Run(cmd.Context(), lint.Options{
-				Debug:   viper.GetBool("lint.debug"),
-				Format:  viper.GetString("lint.format"),
-				Config:  viper.GetString("lint.config"),
-				Path:    viper.GetString("lint.path"),
-				Exclude: strings.Split(viper.GetString("lint.exclude"), ","),
+				Debug:       viper.GetBool("lint.debug"),
+				Format:      format,
+				Config:      viper.GetString("lint.config"),
+				Path:        viper.GetString("lint.path"),
+				Exclude:     strings.Split(viper.GetString("lint.exclude"), ","),
+				Interactive: ui.IsInteractive() && !isJSONOutput(),
+				Proto:       viper.GetBool("lint.proto"),
+				Profile:     cfg.GetProfile(viper.GetString("lint.profile")),
 			})
 		},
 	}
 
 	addLintCommandFlags(cmd)
+	_ = cmd.RegisterFlagCompletionFunc("profile", completeProfiles(cfg))
 
 	return cmd
 }
@@ -53,9 +74,15 @@ func addLintCommandFlags(cmd *cobra.Command) {
 	cmd.Flags().String("path", "./...", "Path to analyze")
 	cmd.Flags().String("exclude", "", "Directories to exclude from validation")
 	cmd.Flags().Bool("debug", false, "Enable debug mode")
+	cmd.Flags().String("config", "", "Custom rules file (revive TOML, or proto rules TOML with --proto)")
+	cmd.Flags().Bool("proto", false, "Lint protobuf files with the mikros conventions")
+	cmd.Flags().String("profile", "default", "Profile with the protobuf repository conventions")
 
 	_ = viper.BindPFlag("lint.format", cmd.Flags().Lookup("format"))
 	_ = viper.BindPFlag("lint.path", cmd.Flags().Lookup("path"))
 	_ = viper.BindPFlag("lint.exclude", cmd.Flags().Lookup("exclude"))
 	_ = viper.BindPFlag("lint.debug", cmd.Flags().Lookup("debug"))
+	_ = viper.BindPFlag("lint.config", cmd.Flags().Lookup("config"))
+	_ = viper.BindPFlag("lint.proto", cmd.Flags().Lookup("proto"))
+	_ = viper.BindPFlag("lint.profile", cmd.Flags().Lookup("profile"))
 }
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/rpc"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/ui"
+)
+
+// mockInfo describes a running mock server.
+type mockInfo struct {
+	GRPCAddress string   `json:"grpc_address"`
+	HTTPAddress string   `json:"http_address,omitempty"`
+	Methods     []string `json:"methods"`
+	Fixtures    int      `json:"fixtures"`
+}
+
+func mockCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Start a local server mocking a protobuf API",
+		Long: `mock starts a local gRPC server answering every method of the services
+declared inside a protobuf API file, so clients can be integrated before
+the services exist. When methods have HTTP bindings (google.api.http
+option), like the ones of http-spec services, an HTTP server following
+them is started as well.
+
+Methods are answered with fixtures, JSON files of a directory matched
+by method and request fields, or with generated sample data.
+
+A fixture file holds a fixture, or an array of them, like:
+
+ {
+   "method": "BillingService/GetInvoice",
+   "request": {"id": "42"},
+   "response": {"id": "42", "status": "STATUS_PAID"}
+ }
+
+Methods returning streams use "responses" instead, and fixtures can
+return errors with "error": {"code": "NOT_FOUND", "message": "..."}.
+When several fixtures match a request, the one matching more fields
+is used.
+
+Examples:
+ # Mock an API with sample data
+ $ mikros mock --proto billing_api.proto
+
+ # Mock an API using fixtures
+ $ mikros mock --proto billing_api.proto --fixtures fixtures
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filename := viper.GetString("mock.proto")
+			if filename == "" {
+				return errors.New("a protobuf API file must be given with --proto")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			server, err := rpc.NewMockServer(&rpc.MockOptions{
+				Proto:    filename,
+				Fixtures: viper.GetString("mock.fixtures"),
+				Handle:   printMockCall,
+			})
+			if err != nil {
+				return err
+			}
+
+			grpcListener, err := net.Listen("tcp", viper.GetString("mock.grpc-addr"))
+			if err != nil {
+				return err
+			}
+
+			info := &mockInfo{
+				GRPCAddress: grpcListener.Addr().String(),
+				Fixtures:    len(server.Fixtures()),
+			}
+			for _, method := range server.Methods() {
+				info.Methods = append(info.Methods, method.Path())
+			}
+
+			var httpListener net.Listener
+			if server.HasHTTP() {
+				httpListener, err = net.Listen("tcp", viper.GetString("mock.http-addr"))
+				if err != nil {
+					_ = grpcListener.Close()
+					return err
+				}
+				info.HTTPAddress = httpListener.Addr().String()
+			}
+
+			if err := printResult(cfg, "Mock server", mockText(info), info); err != nil {
+				return err
+			}
+
+			return server.Serve(ctx, grpcListener, httpListener)
+		},
+	}
+
+	cmd.Flags().String("proto", "", "Sets the protobuf API file to mock.")
+	cmd.Flags().String("fixtures", "", "Sets the directory with fixtures answering methods.")
+	cmd.Flags().String("grpc-addr", "localhost:50051", "Sets the address of the gRPC server.")
+	cmd.Flags().String("http-addr", "localhost:8080", "Sets the address of the HTTP server.")
+	_ = viper.BindPFlag("mock.proto", cmd.Flags().Lookup("proto"))
+	_ = viper.BindPFlag("mock.fixtures", cmd.Flags().Lookup("fixtures"))
+	_ = viper.BindPFlag("mock.grpc-addr", cmd.Flags().Lookup("grpc-addr"))
+	_ = viper.BindPFlag("mock.http-addr", cmd.Flags().Lookup("http-addr"))
+	_ = cmd.RegisterFlagCompletionFunc("proto", completeProtoAPIFiles)
+	_ = cmd.MarkFlagDirname("fixtures")
+
+	return cmd
+}
+
+func mockText(info *mockInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gRPC server listening on %s\n", info.GRPCAddress)
+	if info.HTTPAddress != "" {
+		fmt.Fprintf(&b, "HTTP server listening on %s\n", info.HTTPAddress)
+	}
+
+	b.WriteString("\nMethods:\n")
+	for _, method := range info.Methods {
+		fmt.Fprintf(&b, "  • %s\n", escapeText(method))
+	}
+	fmt.Fprintf(&b, "\n%d fixtures loaded, press Ctrl+C to stop\n", info.Fixtures)
+
+	return b.String()
+}
+
+// printMockCall prints how a request was answered, as it happens.
+func printMockCall(call *rpc.MockCall) {
+	if isJSONOutput() {
+		_ = ui.JSON(call)
+		return
+	}
+
+	answer := "sample data"
+	if call.Fixture != "" {
+		answer = call.Fixture
+	}
+	if call.Error != "" {
+		answer = "❌ " + call.Error
+	}
+
+	fmt.Printf("%s %-4s %s ← %s\n", time.Now().Format(time.TimeOnly), call.Transport, call.Method, answer)
+}
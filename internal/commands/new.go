@@ -1,10 +1,16 @@
 package commands
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/mikros-dev/mikros-cli/internal/plugin"
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/pack"
 	"github.com/mikros-dev/mikros-cli/internal/scaffold/protobuf"
 	protobuf_repository "github.com/mikros-dev/mikros-cli/internal/scaffold/repository/protobuf"
 	service_repository "github.com/mikros-dev/mikros-cli/internal/scaffold/repository/service"
@@ -13,13 +19,31 @@ import (
 	"github.com/mikros-dev/mikros-cli/internal/ui"
 )
 
+var (
+	// projectKinds holds all kinds of projects that can be created with the
+	// new command.
+	projectKinds = []string{
+		"service-template",
+		"protobuf-module",
+		"protobuf-monorepo",
+		"services-monorepo",
+	}
+)
+
 func newCmd(cfg *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "new",
+		Use:   "new [kind]",
 		Short: "Create a new mikros project",
-		Long:  "new helps creating different mikros projects",
+		Long: `new helps creating different mikros projects. The kind of the
+project can be given as argument, otherwise it is asked.
+
+Supported kinds: service-template, protobuf-module, protobuf-monorepo,
+services-monorepo and the names of the template packs installed in
+the packs directory.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeProjectKinds(cfg),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selected, err := runNewProjectForm(cfg)
+			selected, err := selectProjectKind(cfg, args)
 			if err != nil {
 				return err
 			}
@@ -42,15 +66,23 @@ func newCmd(cfg *settings.Settings) *cobra.Command {
 				return nil
 			}
 
-			return nil
+			return newFromPack(cfg, selected)
 		},
 	}
 
 	setNewCmdFlags(cmd)
+	setNewCmdCompletions(cmd, cfg)
 
 	return cmd
 }
 
+// newProjectResult is the result of the new command when using JSON as
+// output format.
+type newProjectResult struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
 func newProtobufRepository(cfg *settings.Settings) error {
 	options := &protobuf_repository.NewOptions{
 		NoVCS:   viper.GetBool("new.no-vcs"),
@@ -58,54 +90,124 @@ func newProtobufRepository(cfg *settings.Settings) error {
 		Profile: viper.GetString("new.profile"),
 	}
 
-	if err := protobuf_repository.New(cfg, options); err != nil {
+	path, err := protobuf_repository.New(cfg, options)
+	if err != nil {
 		return err
 	}
 
-	ui.Message(cfg, "New protobuf repository",
+	return printResult(cfg, "New protobuf repository",
 		"✅ Project successfully created \n\n"+
 			"In order to start, execute the following command inside the new project directory:"+
-			"\n\n$ make setup")
-
-	return nil
+			"\n\n$ make setup",
+		&newProjectResult{Kind: "protobuf-monorepo", Path: path})
 }
 
 func newServiceRepository(cfg *settings.Settings) error {
 	options := &service_repository.NewOptions{
-		NoVCS: viper.GetBool("new.no-vcs"),
-		Path:  viper.GetString("new.path"),
+		NoVCS:   viper.GetBool("new.no-vcs"),
+		Path:    viper.GetString("new.path"),
+		Profile: viper.GetString("new.profile"),
 	}
 
-	if err := service_repository.New(cfg, options); err != nil {
+	path, err := service_repository.New(cfg, options)
+	if err != nil {
 		return err
 	}
 
-	ui.Message(cfg, "New service repository", "✅ Project successfully created")
-	return nil
+	return printResult(cfg, "New service repository", "✅ Project successfully created",
+		&newProjectResult{Kind: "services-monorepo", Path: path})
 }
 
 func newProtobufModule(cfg *settings.Settings) error {
 	options := &protobuf.NewOptions{
-		Profile: viper.GetString("new.profile"),
+		Profile:     viper.GetString("new.profile"),
+		AnswersFile: viper.GetString("new.answers"),
 	}
 
-	return protobuf.New(cfg, options)
+	path, err := protobuf.New(cfg, options)
+	if err != nil {
+		return err
+	}
+
+	return printResult(cfg, "New protobuf module", "✅ Protobuf files successfully created",
+		&newProjectResult{Kind: "protobuf-module", Path: path})
 }
 
 func newServiceTemplate(cfg *settings.Settings) error {
 	options := &service.NewOptions{
 		Path:          viper.GetString("new.path"),
 		ProtoFilename: viper.GetString("new.proto"),
+		Profile:       viper.GetString("new.profile"),
+		Type:          viper.GetString("new.type"),
+		Features:      viper.GetStringSlice("new.features"),
+	}
+	if err := validateServiceFlags(cfg, options); err != nil {
+		return err
 	}
 
-	if err := service.New(cfg, options); err != nil {
+	path, err := service.New(cfg, options)
+	if err != nil {
 		return err
 	}
 
-	ui.Message(cfg, "New service", "✅ Project successfully created")
+	return printResult(cfg, "New service", "✅ Project successfully created",
+		&newProjectResult{Kind: "service-template", Path: path})
+}
+
+// validateServiceFlags checks if the service type and features given as
+// flags are supported, before anything is asked.
+func validateServiceFlags(cfg *settings.Settings, options *service.NewOptions) error {
+	if options.Type != "" {
+		types, err := service.SupportedServiceTypes(cfg)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(types, options.Type) {
+			return fmt.Errorf("unsupported service type '%s', expected one of: %s", options.Type, strings.Join(types, ", "))
+		}
+	}
+
+	if len(options.Features) > 0 {
+		features, err := plugin.GetFeaturesUINames(cfg)
+		if err != nil {
+			return err
+		}
+		for _, name := range options.Features {
+			if len(features) == 0 {
+				return fmt.Errorf("unsupported feature '%s', no feature plugins are installed", name)
+			}
+			if !slices.Contains(features, name) {
+				return fmt.Errorf("unsupported feature '%s', expected one of: %s", name, strings.Join(features, ", "))
+			}
+		}
+	}
+
 	return nil
 }
 
+func newFromPack(cfg *settings.Settings, name string) error {
+	p, err := pack.Get(cfg, name)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return fmt.Errorf("unsupported project kind '%s'", name)
+	}
+
+	options := &pack.NewOptions{
+		Path:    viper.GetString("new.path"),
+		Profile: viper.GetString("new.profile"),
+	}
+
+	path, err := pack.New(cfg, p, options)
+	if err != nil {
+		return err
+	}
+
+	return printResult(cfg, "New "+p.Manifest.Name, "✅ Project successfully created",
+		&newProjectResult{Kind: p.Manifest.Name, Path: path})
+}
+
 func setNewCmdFlags(cmd *cobra.Command) {
 	// path option
 	cmd.Flags().String("path", "", "Sets the output path name (default cwd).")
@@ -115,6 +217,10 @@ func setNewCmdFlags(cmd *cobra.Command) {
 	cmd.Flags().String("proto", "", "Uses an _api.proto file as source for the service API.")
 	_ = viper.BindPFlag("new.proto", cmd.Flags().Lookup("proto"))
 
+	// answers file option
+	cmd.Flags().String("answers", "", "Reads the protobuf-module answers from a JSON file instead of asking them.")
+	_ = viper.BindPFlag("new.answers", cmd.Flags().Lookup("answers"))
+
 	// no-vcs option
 	cmd.Flags().Bool("no-vcs", false, "Disables creating projects with VCS support (default true).")
 	_ = viper.BindPFlag("new.no-vcs", cmd.Flags().Lookup("no-vcs"))
@@ -122,21 +228,64 @@ func setNewCmdFlags(cmd *cobra.Command) {
 	// profile option
 	cmd.Flags().String("profile", "default", "Sets the profile to use.")
 	_ = viper.BindPFlag("new.profile", cmd.Flags().Lookup("profile"))
+
+	// service type option
+	cmd.Flags().String("type", "", "Sets the initial service type of a new service.")
+	_ = viper.BindPFlag("new.type", cmd.Flags().Lookup("type"))
+
+	// features option
+	cmd.Flags().StringSlice("features", nil, "Sets the initial features of a new service.")
+	_ = viper.BindPFlag("new.features", cmd.Flags().Lookup("features"))
+}
+
+func setNewCmdCompletions(cmd *cobra.Command, cfg *settings.Settings) {
+	_ = cmd.RegisterFlagCompletionFunc("profile", completeProfiles(cfg))
+	_ = cmd.RegisterFlagCompletionFunc("proto", completeProtoAPIFiles)
+	_ = cmd.RegisterFlagCompletionFunc("type", completeServiceTypes(cfg))
+	_ = cmd.RegisterFlagCompletionFunc("features", completeFeatures(cfg))
+}
+
+func selectProjectKind(cfg *settings.Settings, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	return runNewProjectForm(cfg)
 }
 
 func runNewProjectForm(cfg *settings.Settings) (string, error) {
-	var selectedProject string
+	if err := ui.RequireInputs(ui.Input{Name: "project kind", Source: "kind argument"}); err != nil {
+		return "", err
+	}
+
+	packs, err := pack.List(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		selectedProject string
+		options         = []huh.Option[string]{
+			huh.NewOption("Application/Service", "service-template"),
+			huh.NewOption("Protobuf module", "protobuf-module"),
+			huh.NewOption("Protobuf repository", "protobuf-monorepo"),
+			huh.NewOption("Services repository", "services-monorepo"),
+		}
+	)
+
+	// Template packs can't replace built-in kinds.
+	for _, p := range packs {
+		if !slices.Contains(projectKinds, p.Manifest.Name) {
+			options = append(options, huh.NewOption(p.Title(), p.Manifest.Name))
+		}
+	}
+	options = append(options, huh.NewOption("Quit", "quit"))
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("Select a project to create or Quit to exit the application").
-				Options(
-					huh.NewOption("Application/Service", "service-template"),
-					huh.NewOption("Protobuf module", "protobuf-module"),
-					huh.NewOption("Protobuf repository", "protobuf-monorepo"),
-					huh.NewOption("Services repository", "services-monorepo"),
-					huh.NewOption("Quit", "quit"),
-				).
+				Options(options...).
 				Value(&selectedProject),
 		),
 	).
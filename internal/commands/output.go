@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/ui"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+func validateOutputFormat() error {
+	switch viper.GetString("output") {
+	case outputText, outputJSON:
+		return nil
+	}
+
+	return fmt.Errorf("unsupported output format '%s'", viper.GetString("output"))
+}
+
+func isJSONOutput() bool {
+	return viper.GetString("output") == outputJSON
+}
+
+// printResult presents the result of a command according to the selected
+// output format: data is used for JSON output while title and text are used
+// otherwise.
+func printResult(cfg *settings.Settings, title, text string, data interface{}) error {
+	if isJSONOutput() {
+		return ui.JSON(data)
+	}
+
+	ui.Message(cfg, title, text)
+	return nil
+}
+
+// escapeText escapes characters that are used as markup by messages, so
+// values like paths are presented as they are.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "_", `\_`, "*", `\*`, "`", "\\`").Replace(s)
+}
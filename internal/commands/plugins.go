@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mikros-dev/mikros-cli/internal/plugin"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+// pluginsResult is the result of the plugins command when using JSON as
+// output format.
+type pluginsResult struct {
+	Services []string `json:"services"`
+	Features []string `json:"features"`
+}
+
+func pluginsCmd(cfg *settings.Settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "plugins",
+		Short: "List installed plugins",
+		Long: `plugins lists the service kinds and features provided by the
+plugins installed in the directories configured in the settings
+file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			services, err := plugin.GetNewServiceKinds(cfg)
+			if err != nil {
+				return err
+			}
+
+			features, err := plugin.GetFeaturesUINames(cfg)
+			if err != nil {
+				return err
+			}
+
+			result := &pluginsResult{
+				Services: append([]string{}, services...),
+				Features: append([]string{}, features...),
+			}
+
+			return printResult(cfg, "Plugins", pluginsText(result), result)
+		},
+	}
+}
+
+func pluginsText(result *pluginsResult) string {
+	var b strings.Builder
+
+	writeList := func(title string, items []string) {
+		b.WriteString(title + ":\n")
+		if len(items) == 0 {
+			b.WriteString("  (none)\n")
+		}
+		for _, item := range items {
+			fmt.Fprintf(&b, "  - %s\n", item)
+		}
+	}
+
+	writeList("Services", result.Services)
+	b.WriteString("\n")
+	writeList("Features", result.Features)
+
+	return b.String()
+}
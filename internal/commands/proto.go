@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func protoCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proto",
+		Short: "Work with protobuf files",
+		Long: `proto groups commands dealing with the protobuf files of a
+protobuf repository.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(protoBreakingCmd(cfg))
+	cmd.AddCommand(protoGenerateCmd(cfg))
+	cmd.AddCommand(protoWatchCmd(cfg))
+
+	return cmd
+}
+
+// protoRoot returns the directory with protobuf files to use: the given one
+// or, otherwise, the proto directory of protobuf repositories when inside
+// one.
+func protoRoot(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	if fs.FindPath("proto") {
+		return "proto"
+	}
+
+	return "."
+}
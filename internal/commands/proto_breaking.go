@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/breaking"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func protoBreakingCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "breaking [path]",
+		Short: "Detect wire-incompatible changes of protobuf files",
+		Long: `breaking compares the protobuf files below path (default proto, when
+it exists, or cwd) with their versions at a git reference, and reports
+changes that break existing clients: removed or renumbered fields, type
+changes, removed messages, enums, services and RPCs, changed HTTP
+paths and verbs and reserved numbers or names that were removed or
+reused.
+
+It fails when breaking changes are found, so it can be used by CI.
+
+Examples:
+ # Compare with the main branch
+ $ mikros proto breaking --against main
+
+ # Compare a directory with the previous commit, as JSON
+ $ mikros proto breaking --against HEAD~1 --output json ./proto
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := breaking.Check(cmd.Context(), &breaking.Options{
+				Against: viper.GetString("proto.breaking.against"),
+				Path:    protoRoot(args),
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := printResult(cfg, "Breaking changes", breakingText(result), result); err != nil {
+				return err
+			}
+			if n := len(result.Changes); n > 0 {
+				return fmt.Errorf("%d breaking changes found", n)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("against", "", "Sets the git reference to compare with.")
+	_ = cmd.MarkFlagRequired("against")
+	_ = viper.BindPFlag("proto.breaking.against", cmd.Flags().Lookup("against"))
+
+	return cmd
+}
+
+func breakingText(result *breaking.Result) string {
+	if len(result.Changes) == 0 {
+		return fmt.Sprintf("✅ No breaking changes found in %d files against %s", result.Files, escapeText(result.Against))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Breaking changes found against %s\n\n", escapeText(result.Against))
+
+	for _, change := range result.Changes {
+		fmt.Fprintf(&b, "  - %s: %s (%s)\n", escapeText(change.File), escapeText(change.Message), escapeText(string(change.Kind)))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/protogen"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func protoGenerateCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate [service]",
+		Short: "Generate code from the protobuf files of services",
+		Long: `generate generates code, mocks and documentation from the protobuf
+files of a protobuf repository, using buf and the plugins of its
+buf.gen.yaml file. It must be executed inside the repository.
+
+It generates a single service, given by its name or by its directory
+inside the proto directory, the services affected by changes since a
+git reference, or all of them. Services are generated in parallel and
+the generated Go modules are kept tidy. Their dependencies can also be
+updated (--update) and they can be built (--check), including mocks
+and test modules, to make sure the generated code compiles.
+
+Examples:
+ # Generate all services
+ $ mikros proto generate
+
+ # Generate a single service
+ $ mikros proto generate billing
+
+ # Generate services changed since the main branch
+ $ mikros proto generate --changed main
+
+ # Generate all services and check that they compile
+ $ mikros proto generate --check
+`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeProtoServices,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options := &protogen.Options{
+				Changed: viper.GetString("proto.generate.changed"),
+				Jobs:    viper.GetInt("proto.generate.jobs"),
+				Update:  viper.GetBool("proto.generate.update"),
+				Check:   viper.GetBool("proto.generate.check"),
+			}
+			if len(args) > 0 {
+				options.Service = args[0]
+			}
+
+			result, err := protogen.Generate(cmd.Context(), options)
+			if err != nil {
+				return err
+			}
+
+			if err := printResult(cfg, "Code generation", generateText(result), result); err != nil {
+				return err
+			}
+			if n := len(result.Failed()); n > 0 {
+				return fmt.Errorf("%d services failed to generate", n)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("changed", "", "Generates only services changed since a git reference.")
+	cmd.Flags().Int("jobs", 0, "Sets how many services are generated at the same time (default: number of CPUs).")
+	_ = viper.BindPFlag("proto.generate.changed", cmd.Flags().Lookup("changed"))
+	cmd.Flags().Bool("update", false, "Updates the dependencies of the generated Go modules.")
+	cmd.Flags().Bool("check", false, "Builds the generated Go modules.")
+	_ = viper.BindPFlag("proto.generate.jobs", cmd.Flags().Lookup("jobs"))
+	_ = viper.BindPFlag("proto.generate.update", cmd.Flags().Lookup("update"))
+	_ = viper.BindPFlag("proto.generate.check", cmd.Flags().Lookup("check"))
+
+	return cmd
+}
+
+func generateText(result *protogen.Result) string {
+	if len(result.Services) == 0 {
+		return "✅ No services to generate"
+	}
+
+	var b strings.Builder
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(&b, "⚠️ %s\n", escapeText(warning))
+	}
+	if len(result.Warnings) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, service := range result.Services {
+		if service.Error != "" {
+			fmt.Fprintf(&b, "  ❌ %s: %s\n", escapeText(service.Name), escapeText(service.Error))
+			continue
+		}
+
+		fmt.Fprintf(&b, "  ✅ %s (%s)\n", escapeText(service.Name), service.Duration)
+	}
+
+	state := "tidy"
+	switch {
+	case result.Updated && result.Checked:
+		state = "updated, tidy and built"
+	case result.Updated:
+		state = "updated and tidy"
+	case result.Checked:
+		state = "tidy and built"
+	}
+	for _, module := range result.Modules {
+		fmt.Fprintf(&b, "\nModule %s is %s\n", escapeText(module), state)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+	"github.com/mikros-dev/mikros-cli/internal/protogen"
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/service"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/ui"
+	"github.com/mikros-dev/mikros-cli/internal/upgrade"
+)
+
+// watchedService is a service whose handler stubs are refreshed when its
+// protobuf API changes.
+type watchedService struct {
+	// Path is the service directory.
+	Path string
+
+	// ProtoFile is the service protobuf API file.
+	ProtoFile string
+
+	// ProtoService is the directory of ProtoFile inside the proto directory
+	// of its repository, i.e., its name for code generation.
+	ProtoService string
+}
+
+func protoWatchCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Generate code whenever protobuf files change",
+		Long: `watch watches the protobuf files of a protobuf repository and,
+whenever they change, generates code again for the affected services
+only, printing generation errors as they happen.
+
+When executed inside a service directory, created with a protobuf API
+file, it watches the repository of that file and also refreshes the
+service handler stubs when the API changes, merging them like the
+upgrade command does. When the merge would leave conflicts, the service
+is not changed and the conflicting files are reported instead.
+
+Examples:
+ # Watch the protobuf repository of the current directory
+ $ mikros proto watch
+
+ # Watch the API of the service in the current directory
+ $ cd services/billing && mikros proto watch
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			svc, err := loadWatchedService()
+			if err != nil {
+				return err
+			}
+
+			var path string
+			if svc != nil {
+				path = filepath.Dir(svc.ProtoFile)
+			}
+
+			root, err := protogen.RepositoryRoot(ctx, path)
+			if err != nil {
+				return err
+			}
+			if svc != nil {
+				if err := svc.setProtoService(root); err != nil {
+					return err
+				}
+			}
+
+			if !isJSONOutput() {
+				fmt.Printf("Watching %s for changes, press Ctrl+C to stop\n", filepath.Join(root, "proto"))
+			}
+
+			return protogen.Watch(ctx, root, viper.GetDuration("proto.watch.debounce"), func(files []string) {
+				regenerate(ctx, cfg, root, svc, files)
+			})
+		},
+	}
+
+	cmd.Flags().Duration("debounce", protogen.DefaultDebounce, "Sets how long to wait for more changes before generating code.")
+	cmd.Flags().Int("jobs", 0, "Sets how many services are generated at the same time (default: number of CPUs).")
+	_ = viper.BindPFlag("proto.watch.debounce", cmd.Flags().Lookup("debounce"))
+	_ = viper.BindPFlag("proto.watch.jobs", cmd.Flags().Lookup("jobs"))
+
+	return cmd
+}
+
+// loadWatchedService returns the service of the current directory, if it
+// was created with a protobuf API file.
+func loadWatchedService() (*watchedService, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load(cwd)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not load generation manifest: %w", err)
+	}
+
+	protoFile, _ := m.Answers["proto_file"].(string)
+	if m.Kind != "service-template" || protoFile == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(protoFile) {
+		protoFile = filepath.Join(cwd, protoFile)
+	}
+
+	return &watchedService{
+		Path:      cwd,
+		ProtoFile: protoFile,
+	}, nil
+}
+
+func (s *watchedService) setProtoService(root string) error {
+	// The repository root has symbolic links resolved, so the file must
+	// have them resolved as well.
+	dir, err := filepath.EvalSymlinks(filepath.Dir(s.ProtoFile))
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(filepath.Join(root, "proto"), dir)
+	if err != nil {
+		return err
+	}
+
+	s.ProtoService = filepath.ToSlash(rel)
+	return nil
+}
+
+// regenerate generates code for the services affected by changed files and,
+// when the watched service is one of them, refreshes its handlers. Errors
+// are printed instead of stopping the watch.
+func regenerate(ctx context.Context, cfg *settings.Settings, root string, svc *watchedService, files []string) {
+	result, err := protogen.Generate(ctx, &protogen.Options{
+		Path:  root,
+		Files: files,
+		Jobs:  viper.GetInt("proto.watch.jobs"),
+	})
+	if err != nil {
+		printWatchError(cfg, "Code generation failed", err)
+		return
+	}
+	if len(result.Services) == 0 {
+		return
+	}
+
+	title := fmt.Sprintf("Code generation (%s)", time.Now().Format(time.TimeOnly))
+	if err := printResult(cfg, title, generateText(result), result); err != nil {
+		printWatchError(cfg, "Code generation failed", err)
+		return
+	}
+
+	if svc == nil || !generated(result, svc.ProtoService) {
+		return
+	}
+
+	// Only the handlers are refreshed, and only when they merge cleanly, so
+	// that conflict markers are never written while the service is being
+	// worked on.
+	upgraded, err := upgradeProject(cfg, svc.Path, &upgrade.Options{
+		DryRun: true,
+		Files:  []string{service.HandlersFile},
+	})
+	if err != nil {
+		printWatchError(cfg, "Handlers refresh failed", err)
+		return
+	}
+	if conflicts := upgraded.Conflicts(); len(conflicts) > 0 {
+		if err := printResult(cfg, "Handlers", conflictsText(conflicts), upgraded); err != nil {
+			printWatchError(cfg, "Handlers refresh failed", err)
+		}
+		return
+	}
+
+	upgraded, err = upgradeProject(cfg, svc.Path, &upgrade.Options{
+		Files: []string{service.HandlersFile},
+	})
+	if err != nil {
+		printWatchError(cfg, "Handlers refresh failed", err)
+		return
+	}
+	if err := printResult(cfg, "Handlers", upgradeText(upgraded, false), upgraded); err != nil {
+		printWatchError(cfg, "Handlers refresh failed", err)
+	}
+}
+
+func conflictsText(conflicts []string) string {
+	var b strings.Builder
+	b.WriteString("⚠️ Handlers not refreshed, since these files would have conflicts:\n\n")
+	for _, filename := range conflicts {
+		fmt.Fprintf(&b, "  - %s\n", escapeText(filename))
+	}
+	b.WriteString("\nExecute 'mikros upgrade' to merge them.\n")
+
+	return b.String()
+}
+
+// generated checks if a service was successfully generated.
+func generated(result *protogen.Result, service string) bool {
+	for _, s := range result.Services {
+		if s.Name == service {
+			return s.Error == ""
+		}
+	}
+
+	return false
+}
+
+func printWatchError(cfg *settings.Settings, title string, err error) {
+	if isJSONOutput() {
+		_ = ui.JSON(map[string]string{"error": err.Error()})
+		return
+	}
+
+	ui.Message(cfg, title, "❌ "+escapeText(err.Error()))
+}
@@ -2,13 +2,22 @@ package commands
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func rootCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "mikros",
 		Short: "A \"swiss army knife\" for dealing with mikros framework tasks.",
 		Long: `mikros is a command to help the developer use the mikros
 framework to create new services.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutputFormat()
+		},
 	}
+
+	cmd.PersistentFlags().String("output", outputText, "Sets the output format of command results (text, json).")
+	_ = viper.BindPFlag("output", cmd.PersistentFlags().Lookup("output"))
+
+	return cmd
 }
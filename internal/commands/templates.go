@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func templatesCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage templates used to create new projects",
+		Long: `templates helps customizing the templates used by the new command.
+
+Templates are searched in the directory set by the templates_dir
+option of the selected profile, then inside the .mikros/templates
+directory of the current project and, finally, in the built-in ones.
+Templates are replaced by name, and templates that do not exist in
+the built-in set are also generated.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(templatesExportCmd(cfg))
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/protobuf"
+	protobuf_repository "github.com/mikros-dev/mikros-cli/internal/scaffold/repository/protobuf"
+	service_repository "github.com/mikros-dev/mikros-cli/internal/scaffold/repository/service"
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/service"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/template"
+)
+
+// templatesExportResult is the result of the templates export command when
+// using JSON as output format.
+type templatesExportResult struct {
+	Path  string   `json:"path"`
+	Files []string `json:"files"`
+}
+
+func templatesExportCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the built-in templates",
+		Long: `export writes all built-in templates into a directory, keeping the
+layout expected when searching for templates, so they can be used
+as a starting point for customized ones.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				path      = viper.GetString("templates.export.path")
+				overwrite = viper.GetBool("templates.export.force")
+				sets      []template.Set
+				files     []string
+			)
+
+			sets = append(sets, service.TemplateSets()...)
+			sets = append(sets, protobuf.TemplateSets()...)
+			sets = append(sets, service_repository.TemplateSets()...)
+			sets = append(sets, protobuf_repository.TemplateSets()...)
+
+			for _, set := range sets {
+				written, err := set.Export(path, overwrite)
+				if err != nil {
+					return fmt.Errorf("could not export templates: %w", err)
+				}
+				files = append(files, written...)
+			}
+
+			result := &templatesExportResult{
+				Path:  path,
+				Files: files,
+			}
+
+			return printResult(cfg, "Templates export", templatesExportText(result), result)
+		},
+	}
+
+	cmd.Flags().String("path", template.ProjectTemplatesDir, "Sets the destination directory.")
+	_ = viper.BindPFlag("templates.export.path", cmd.Flags().Lookup("path"))
+	_ = cmd.MarkFlagDirname("path")
+
+	cmd.Flags().Bool("force", false, "Overwrites existing files.")
+	_ = viper.BindPFlag("templates.export.force", cmd.Flags().Lookup("force"))
+
+	return cmd
+}
+
+func templatesExportText(result *templatesExportResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "✅ Templates exported to %s\n\n", escapeText(result.Path))
+	for _, file := range result.Files {
+		fmt.Fprintf(&b, "  - %s\n", escapeText(file))
+	}
+
+	return b.String()
+}
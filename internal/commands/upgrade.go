@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/pack"
+	protobuf_repository "github.com/mikros-dev/mikros-cli/internal/scaffold/repository/protobuf"
+	service_repository "github.com/mikros-dev/mikros-cli/internal/scaffold/repository/service"
+	"github.com/mikros-dev/mikros-cli/internal/scaffold/service"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/template"
+	"github.com/mikros-dev/mikros-cli/internal/upgrade"
+)
+
+func upgradeCmd(cfg *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade [path]",
+		Short: "Regenerate a project with the current templates",
+		Long: `upgrade generates a project again, using the answers recorded in its
+.mikros/manifest.json file and the current templates and plugins,
+and merges the result into it (default cwd).
+
+Files not changed since they were generated are replaced. Files
+changed by users are three-way merged with the regenerated ones,
+using their previously generated content as base. When both changed
+the same region, conflict markers are left in the file.
+
+Examples:
+ # Upgrade the project in the current directory
+ $ mikros upgrade
+
+ # Only show what would change
+ $ mikros upgrade --dry-run ./services/user
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			result, err := upgradeProject(cfg, path, &upgrade.Options{
+				DryRun: viper.GetBool("upgrade.dry-run"),
+			})
+			if err != nil {
+				return err
+			}
+
+			return printResult(cfg, "Upgrade", upgradeText(result, viper.GetBool("upgrade.dry-run")), result)
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Only shows what would change, without changing any file.")
+	_ = viper.BindPFlag("upgrade.dry-run", cmd.Flags().Lookup("dry-run"))
+
+	cmd.Flags().String("profile", "default", "Sets the profile to use.")
+	_ = viper.BindPFlag("upgrade.profile", cmd.Flags().Lookup("profile"))
+	_ = cmd.RegisterFlagCompletionFunc("profile", completeProfiles(cfg))
+
+	return cmd
+}
+
+func upgradeProject(cfg *settings.Settings, path string, options *upgrade.Options) (*upgrade.Result, error) {
+	projectPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := manifest.Load(projectPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("'%s' does not have a %s file, only projects created by mikros can be upgraded", path, manifest.Filename)
+		}
+
+		return nil, fmt.Errorf("could not load generation manifest: %w", err)
+	}
+
+	tmpPath, err := os.MkdirTemp("", "mikros-upgrade-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpPath)
+	}()
+
+	generatedPath, err := regenerateProject(cfg, m, projectPath, tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not regenerate project: %w", err)
+	}
+
+	return upgrade.Merge(projectPath, m, generatedPath, options)
+}
+
+func regenerateProject(cfg *settings.Settings, m *manifest.Manifest, projectPath, path string) (string, error) {
+	// Custom templates of the project must be found, so search paths are
+	// created inside it.
+	cwd, err := fs.ChangeDir(projectPath)
+	if err != nil {
+		return "", err
+	}
+	searchPaths := template.NewSearchPaths(cfg.GetProfile(viper.GetString("upgrade.profile")).TemplatesDir)
+	if err := os.Chdir(cwd); err != nil {
+		return "", err
+	}
+
+	switch kind := m.Kind; {
+	case kind == "service-template":
+		return service.Regenerate(cfg, m, &service.RegenerateOptions{
+			ProjectPath: projectPath,
+			Path:        path,
+			SearchPaths: searchPaths,
+		})
+
+	case kind == "services-monorepo":
+		return service_repository.Regenerate(m, path, searchPaths)
+
+	case kind == "protobuf-monorepo":
+		return protobuf_repository.Regenerate(m, path, searchPaths)
+
+	case strings.HasPrefix(kind, pack.KindPrefix):
+		return pack.Regenerate(cfg, m, path)
+	}
+
+	return "", fmt.Errorf("unsupported project kind '%s'", m.Kind)
+}
+
+func upgradeText(result *upgrade.Result, dryRun bool) string {
+	var (
+		b      strings.Builder
+		counts = make(map[upgrade.Status]int)
+	)
+
+	switch {
+	case dryRun:
+		b.WriteString("Nothing was changed (dry run)\n\n")
+	case len(result.Conflicts()) > 0:
+		b.WriteString("Project upgraded with conflicts, resolve them before committing\n\n")
+	default:
+		b.WriteString("✅ Project successfully upgraded\n\n")
+	}
+
+	for _, f := range result.Files {
+		counts[f.Status]++
+		if f.Status != upgrade.StatusUnchanged {
+			fmt.Fprintf(&b, "  - %s: %s\n", escapeText(f.Path), f.Status)
+		}
+	}
+
+	var summary []string
+	for _, status := range []upgrade.Status{
+		upgrade.StatusUpdated,
+		upgrade.StatusMerged,
+		upgrade.StatusConflict,
+		upgrade.StatusAdded,
+		upgrade.StatusDeleted,
+		upgrade.StatusObsolete,
+		upgrade.StatusUnchanged,
+	} {
+		if n := counts[status]; n > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	fmt.Fprintf(&b, "\n%s\n", strings.Join(summary, ", "))
+
+	return b.String()
+}
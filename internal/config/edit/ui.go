@@ -54,7 +54,7 @@ func initialForm(cfg *settings.Settings) (string, error) {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "settings section"); err != nil {
 		return "", err
 	}
 
@@ -62,6 +62,12 @@ func initialForm(cfg *settings.Settings) (string, error) {
 }
 
 func settingsForm(cfg *settings.Settings) error {
+	var themes []huh.Option[string]
+	for _, name := range cfg.ThemeNames() {
+		themes = append(themes, huh.NewOption(name, name))
+	}
+	themes = append(themes, huh.NewOption("default", "default"))
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().Title("Feature plugins:").Value(&cfg.Paths.Plugins.Features),
@@ -72,20 +78,14 @@ func settingsForm(cfg *settings.Settings) error {
 			huh.NewConfirm().Title("Enable accessibility?").Value(&cfg.UI.Accessible),
 			huh.NewSelect[string]().
 				Title("Select the color theme to use:").
-				Options(
-					huh.NewOption("base16", "base16"),
-					huh.NewOption("charm", "charm"),
-					huh.NewOption("dracula", "dracula"),
-					huh.NewOption("catppuccin", "catppuccin"),
-					huh.NewOption("default", "default"),
-				).
+				Options(themes...).
 				Value(&cfg.UI.Theme),
 		).Title("UI\n"),
 	).
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	return form.Run()
+	return ui.RunForm(form, "feature plugins path", "service plugins path", "accessibility", "theme")
 }
 
 func getProfileEntries(cfg *settings.Settings, withMainMenu bool) []huh.Option[string] {
@@ -125,7 +125,7 @@ loop:
 			WithAccessible(cfg.UI.Accessible).
 			WithTheme(cfg.GetTheme())
 
-		if err := form.Run(); err != nil {
+		if err := ui.RunForm(form, "profile action"); err != nil {
 			return err
 		}
 
@@ -168,7 +168,7 @@ func addProfile(cfg *settings.Settings) error {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "profile name"); err != nil {
 		return err
 	}
 
@@ -203,7 +203,7 @@ func removeProfile(cfg *settings.Settings) error {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "profiles to remove"); err != nil {
 		return err
 	}
 	if len(names) != 0 {
@@ -243,6 +243,10 @@ func editProfile(cfg *settings.Settings, name string) error {
 				Value(&profile.Project.ProtobufMonorepo.VcsPath).
 				Validate(ui.IsEmpty("VCS path prefix cannot be empty")),
 
+			huh.NewInput().
+				Title("Services VCS path prefix. Enter the module path prefix of services created without a fully qualified name (optional):").
+				Value(&profile.Project.Service.VcsPath),
+
 			huh.NewInput().
 				Title("Auth scopes. Enter the authentication scopes key for HTTP services:").
 				Value(&profile.Project.Templates.Protobuf.CustomAuthName).
@@ -252,7 +256,7 @@ func editProfile(cfg *settings.Settings, name string) error {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "profile name", "repository name", "project name", "VCS path prefix", "services VCS path prefix", "auth scopes"); err != nil {
 		return err
 	}
 
@@ -278,7 +282,7 @@ func confirmSave(cfg *settings.Settings) error {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "save confirmation"); err != nil {
 		return err
 	}
 
@@ -1,6 +1,12 @@
 package git
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -58,3 +64,131 @@ func Init() (*Git, error) {
 func (g *Git) IsValidRepository() bool {
 	return g.isRepository
 }
+
+// MergeFile performs a three-way merge of the changes between base and other
+// into current, like 'git merge-file'. Labels name current, base and other
+// inside conflict markers. It returns the merged content and the number of
+// conflicts found.
+func MergeFile(current, base, other []byte, labels [3]string) ([]byte, int, error) {
+	dir, err := os.MkdirTemp("", "mikros-merge-")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	args := []string{"git", "merge-file", "-p"}
+	for _, label := range labels {
+		args = append(args, "-L", label)
+	}
+
+	for i, content := range [][]byte{current, base, other} {
+		filename := filepath.Join(dir, fmt.Sprintf("%d", i))
+		if err := os.WriteFile(filename, content, 0644); err != nil {
+			return nil, 0, err
+		}
+		args = append(args, filename)
+	}
+
+	// The exit code is the number of conflicts, or negative on errors.
+	out, code, err := process.ExecContext(context.Background(), args...)
+	if err != nil && (code <= 0 || code > 127) {
+		return nil, 0, fmt.Errorf("could not merge file: %w", err)
+	}
+
+	return out, code, nil
+}
+
+// TopLevel returns the root path of the repository containing dir.
+func TopLevel(ctx context.Context, dir string) (string, error) {
+	out, _, err := process.ExecContext(ctx, "git", "-C", dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not inside a git repository: %w", dir, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Export writes the files of the repository at root, as they are at ref and
+// below path (relative to root), into dest. Nothing is written when path did
+// not exist at ref.
+func Export(ctx context.Context, root, ref, path, dest string) error {
+	if _, _, err := process.ExecContext(ctx, "git", "-C", root, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return fmt.Errorf("unknown git reference '%s'", ref)
+	}
+
+	files, _, err := process.ExecContext(ctx, "git", "-C", root, "ls-tree", "-r", "--name-only", ref, "--", path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	archive, _, err := process.ExecContext(ctx, "git", "-C", root, "archive", "--format=tar", ref, "--", path)
+	if err != nil {
+		return err
+	}
+
+	return extractTar(archive, dest)
+}
+
+// ChangedFiles returns the files of the repository at root, below path
+// (relative to root), that were changed, added or removed since ref. Files
+// not tracked yet are also included. Names are relative to root.
+func ChangedFiles(ctx context.Context, root, ref, path string) ([]string, error) {
+	if _, _, err := process.ExecContext(ctx, "git", "-C", root, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown git reference '%s'", ref)
+	}
+
+	changed, _, err := process.ExecContext(ctx, "git", "-C", root, "diff", "--name-only", ref, "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, _, err := process.ExecContext(ctx, "git", "-C", root, "ls-files", "--others", "--exclude-standard", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(changed)+string(untracked), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+
+	return files, nil
+}
+
+func extractTar(archive []byte, dest string) error {
+	r := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Only regular files are needed, and entries must not leave dest.
+		if header.Typeflag != tar.TypeReg || !filepath.IsLocal(header.Name) {
+			continue
+		}
+
+		filename := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, content, 0644); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package golden helps testing generated projects against golden files
+// checked in the repository.
+//
+// Golden files are kept with the ".golden" suffix, so they are never used by
+// tools as the files they represent (like go.mod or .gitignore files). When
+// generated code changes on purpose, they can be updated with:
+//
+//	go test ./... -update
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+)
+
+const suffix = ".golden"
+
+var update = flag.Bool("update", false, "updates golden files with the generated ones")
+
+// ignored holds files not generated by templates, whose content depends on
+// the environment.
+var ignored = []string{"go.mod", "go.sum", manifest.BaseDir}
+
+// Compare checks if the files generated inside dir are equal to the golden
+// files inside goldenDir. With -update, golden files are replaced by the
+// generated ones.
+func Compare(t *testing.T, dir, goldenDir string) {
+	t.Helper()
+
+	got, err := readTree(dir, "")
+	if err != nil {
+		t.Fatalf("could not read generated files: %v", err)
+	}
+
+	if *update {
+		if err := writeTree(goldenDir, got); err != nil {
+			t.Fatalf("could not update golden files: %v", err)
+		}
+
+		return
+	}
+
+	want, err := readTree(goldenDir, suffix)
+	if err != nil {
+		t.Fatalf("could not read golden files (run with -update to create them): %v", err)
+	}
+
+	compare(t, got, want)
+}
+
+// CompareDirs checks if the files generated inside dir are equal to the ones
+// generated inside wantDir.
+func CompareDirs(t *testing.T, dir, wantDir string) {
+	t.Helper()
+
+	got, err := readTree(dir, "")
+	if err != nil {
+		t.Fatalf("could not read generated files: %v", err)
+	}
+
+	want, err := readTree(wantDir, "")
+	if err != nil {
+		t.Fatalf("could not read generated files: %v", err)
+	}
+
+	compare(t, got, want)
+}
+
+func compare(t *testing.T, got, want map[string][]byte) {
+	t.Helper()
+
+	for _, name := range sortedKeys(want) {
+		content, ok := got[name]
+		if !ok {
+			t.Errorf("%s: not generated", name)
+			continue
+		}
+		if !bytes.Equal(content, want[name]) {
+			t.Errorf("%s: %s", name, diff(want[name], content))
+		}
+	}
+
+	for _, name := range sortedKeys(got) {
+		if _, ok := want[name]; !ok {
+			t.Errorf("%s: generated but not expected", name)
+		}
+	}
+}
+
+func readTree(root, trimSuffix string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(rel), trimSuffix)
+
+		if slices.Contains(ignored, name) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if name == manifest.Filename {
+			data, err = normalizeManifest(data)
+			if err != nil {
+				return err
+			}
+		}
+		files[name] = data
+
+		return nil
+	})
+
+	return files, err
+}
+
+// normalizeManifest removes from a generation manifest everything that
+// changes between executions.
+func normalizeManifest(data []byte) ([]byte, error) {
+	var m manifest.Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	m.CLIVersion = ""
+	m.GeneratedAt = time.Time{}
+	for _, p := range m.Plugins {
+		p.Version = ""
+		p.Checksum = ""
+	}
+
+	b, err := json.MarshalIndent(&m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+func writeTree(root string, files map[string][]byte) error {
+	if err := os.RemoveAll(root); err != nil {
+		return err
+	}
+
+	for name, content := range files {
+		filename := filepath.Join(root, filepath.FromSlash(name)+suffix)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for k := range files {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// diff describes the first difference between want and got.
+func diff(want, got []byte) string {
+	var (
+		wantLines = strings.Split(string(want), "\n")
+		gotLines  = strings.Split(string(got), "\n")
+	)
+
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("differs at line %d\n\twant: %s\n\tgot:  %s", i+1, w, g)
+		}
+	}
+
+	return "differs"
+}
+
+// BuildModule builds every package of the Go module generated at dir. Its
+// dependency on mikros uses the same module used by the CLI. It is skipped
+// in short mode, since it may need to download modules.
+func BuildModule(t *testing.T, dir string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("building generated code is skipped in short mode")
+	}
+
+	mikrosDir := strings.TrimSpace(string(goCmd(t, "", "list", "-m", "-f", "{{.Dir}}", "github.com/mikros-dev/mikros")))
+	goCmd(t, dir, "mod", "edit",
+		"-require=github.com/mikros-dev/mikros@v0.0.0",
+		"-replace=github.com/mikros-dev/mikros="+mikrosDir,
+	)
+	goCmd(t, dir, "mod", "tidy")
+	goCmd(t, dir, "build", "./...")
+}
+
+// BuildPlugin builds the plugin of the Go module at dir into output. It is
+// skipped in short mode, since it may need to download modules.
+func BuildPlugin(t *testing.T, dir, output string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("building plugins is skipped in short mode")
+	}
+
+	goCmd(t, dir, "build", "-o", output, ".")
+}
+
+// goCmd executes a go command inside dir, independently of the flags used
+// by the test itself.
+func goCmd(t *testing.T, dir string, args ...string) []byte {
+	t.Helper()
+
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(slices.DeleteFunc(os.Environ(), func(env string) bool {
+		return strings.HasPrefix(env, "GOFLAGS=")
+	}), "GOFLAGS=-mod=mod")
+
+	out, err := cmd.Output()
+	if err != nil {
+		var (
+			stderr  []byte
+			exitErr *exec.ExitError
+		)
+		if errors.As(err, &exitErr) {
+			stderr = exitErr.Stderr
+		}
+		t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, stderr)
+	}
+
+	return out
+}
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+)
+
+const (
+	historyFilename = "$HOME/.mikros/history.toml"
+)
+
+// History represents the answers previously given by the user, grouped by
+// profile, so they can be used as default values in new surveys.
+type History struct {
+	Profile map[string]*Answers `toml:"profile"`
+}
+
+// Answers represents the answers remembered for a single profile.
+type Answers struct {
+	Product  string   `toml:"product,omitempty"`
+	Language string   `toml:"language,omitempty"`
+	Type     string   `toml:"type,omitempty"`
+	Features []string `toml:"features,omitempty"`
+
+	// Plugins holds the answers given to plugin surveys, indexed by the
+	// survey name and then by the question name.
+	Plugins map[string]map[string]interface{} `toml:"plugins,omitempty"`
+}
+
+// Load retrieves the answers history from its file, returning an empty
+// history if it does not exist yet.
+func Load() (*History, error) {
+	h := &History{}
+
+	if name, ok := FileExists(); ok {
+		if _, err := toml.DecodeFile(name, h); err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+// FileExists checks if the history file exists and returns its expanded path.
+func FileExists() (string, bool) {
+	name := os.ExpandEnv(historyFilename)
+	return name, fs.FindPath(name)
+}
+
+// Clear removes the history of a profile. If profile is empty, the whole
+// history is removed.
+func Clear(profile string) error {
+	name, ok := FileExists()
+	if !ok {
+		return nil
+	}
+
+	if profile == "" {
+		return os.Remove(name)
+	}
+
+	h, err := Load()
+	if err != nil {
+		return err
+	}
+	delete(h.Profile, profile)
+
+	return h.Write()
+}
+
+// Get returns the answers remembered for a profile, creating an empty entry
+// if the profile does not have one.
+func (h *History) Get(profile string) *Answers {
+	if h.Profile == nil {
+		h.Profile = make(map[string]*Answers)
+	}
+
+	a, ok := h.Profile[profile]
+	if !ok {
+		a = &Answers{}
+		h.Profile[profile] = a
+	}
+
+	return a
+}
+
+// Write saves the current History instance to its file using the TOML format.
+func (h *History) Write() error {
+	var basePath = os.ExpandEnv(historyFilename)
+
+	if _, err := fs.CreatePath(filepath.Dir(basePath)); err != nil {
+		return err
+	}
+
+	file, err := os.Create(basePath)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	en := toml.NewEncoder(file)
+	return en.Encode(h)
+}
+
+// PluginAnswers returns the answers previously given to the plugin survey
+// identified by name.
+func (a *Answers) PluginAnswers(name string) map[string]interface{} {
+	return a.Plugins[name]
+}
+
+// SetPluginAnswers remembers the answers given to a plugin survey. Only
+// answers that can be used as default values for questions are kept.
+func (a *Answers) SetPluginAnswers(name string, answers map[string]interface{}) {
+	values := make(map[string]interface{})
+	for k, v := range answers {
+		switch v.(type) {
+		case string, bool, []string:
+			values[k] = v
+		}
+	}
+
+	if a.Plugins == nil {
+		a.Plugins = make(map[string]map[string]interface{})
+	}
+	a.Plugins[name] = values
+}
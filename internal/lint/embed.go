@@ -6,3 +6,6 @@ import (
 
 //go:embed config/revive.toml.tmpl
 var reviveConfig embed.FS
+
+//go:embed config/proto.toml.tmpl
+var protoConfig embed.FS
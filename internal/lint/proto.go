@@ -0,0 +1,236 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// protoConfigContext holds the conventions of protobuf repositories used by
+// the default proto rules configuration.
+type protoConfigContext struct {
+	ProjectName     string
+	GoPackagePrefix string
+}
+
+// protoLintConfig is the configuration of proto rules. Only the rules it
+// declares are applied.
+type protoLintConfig struct {
+	Severity string                      `toml:"severity"`
+	Rules    map[string]*protoRuleConfig `toml:"rule"`
+}
+
+type protoRuleConfig struct {
+	Severity  string            `toml:"severity"`
+	Disabled  bool              `toml:"disabled"`
+	Arguments map[string]string `toml:"arguments"`
+}
+
+func runProtoLint(opts Options) error {
+	config, err := loadProtoConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	filenames, err := findProtoFiles(opts.Path, opts.Exclude)
+	if err != nil {
+		return err
+	}
+
+	var failures []*protoFailure
+	for _, filename := range filenames {
+		p, err := protobuf.Parse(filename)
+		if err != nil {
+			return err
+		}
+
+		failures = append(failures, applyProtoRules(p, config)...)
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = "default"
+	}
+
+	return protoFormatters[format](os.Stdout, failures)
+}
+
+func loadProtoConfig(opts Options) (*protoLintConfig, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if opts.Config != "" {
+		data, err = os.ReadFile(opts.Config)
+	} else {
+		data, err = defaultProtoConfig(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config protoLintConfig
+	if _, err := toml.Decode(string(data), &config); err != nil {
+		return nil, fmt.Errorf("could not parse proto rules configuration: %w", err)
+	}
+
+	for name, rule := range config.Rules {
+		if _, ok := protoRules[name]; !ok {
+			return nil, fmt.Errorf("unknown proto rule '%s'", name)
+		}
+		if rule.Severity == "" {
+			rule.Severity = config.Severity
+		}
+		if rule.Severity == "" {
+			rule.Severity = severityWarning
+		}
+		if rule.Severity != severityError && rule.Severity != severityWarning {
+			return nil, fmt.Errorf("unsupported severity '%s' of proto rule '%s'", rule.Severity, name)
+		}
+	}
+
+	return &config, nil
+}
+
+func defaultProtoConfig(opts Options) ([]byte, error) {
+	data, err := protoConfig.ReadFile("config/proto.toml.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New("config").Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var ctx protoConfigContext
+	if opts.Profile != nil {
+		monorepo := opts.Profile.Project.ProtobufMonorepo
+		ctx.ProjectName = monorepo.ProjectName
+		if monorepo.VcsPath != "" && monorepo.RepositoryName != "" {
+			ctx.GoPackagePrefix = monorepo.VcsPath + "/" + monorepo.RepositoryName
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, ctx); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// findProtoFiles returns the protobuf files below path, which may use the
+// "./..." notation of Go packages, except the excluded ones.
+func findProtoFiles(path string, exclude []string) ([]string, error) {
+	root := strings.TrimSuffix(path, "...")
+	if root == "" {
+		root = "."
+	}
+
+	var filenames []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if isExcluded(path, exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+		if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".proto" {
+			filenames = append(filenames, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+// isExcluded checks path against exclusions, which are file names or
+// directories followed by "/...".
+func isExcluded(path string, exclude []string) bool {
+	path = filepath.Clean(path)
+
+	for _, e := range exclude {
+		if e == "" {
+			continue
+		}
+
+		if dir, ok := strings.CutSuffix(e, "/..."); ok {
+			dir = filepath.Clean(dir)
+			if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+				return true
+			}
+			continue
+		}
+
+		if path == filepath.Clean(e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyProtoRules(p *protobuf.Proto, config *protoLintConfig) []*protoFailure {
+	names := make([]string, 0, len(config.Rules))
+	for name := range config.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []*protoFailure
+	for _, name := range names {
+		ruleConfig := config.Rules[name]
+		if ruleConfig.Disabled {
+			continue
+		}
+
+		rule := protoRules[name]
+		for _, f := range rule.check(p, ruleConfig.Arguments) {
+			failures = append(failures, &protoFailure{
+				Severity:   ruleConfig.Severity,
+				Failure:    f.message,
+				RuleName:   name,
+				Category:   rule.category,
+				Confidence: 1,
+				Position: protoFailurePosition{
+					Start: newProtoPosition(p.Filename, f.position),
+					End:   newProtoPosition(p.Filename, f.position),
+				},
+			})
+		}
+	}
+
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].Position.Start.Line < failures[j].Position.Start.Line
+	})
+
+	return failures
+}
@@ -0,0 +1,217 @@
+package lint
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+// protoFailure is a failure of a proto rule. It has the same fields of
+// revive failures, so both can be handled by the same tools.
+type protoFailure struct {
+	Severity        string
+	Failure         string
+	RuleName        string
+	Category        string
+	Position        protoFailurePosition
+	Confidence      float64
+	ReplacementLine string
+}
+
+type protoFailurePosition struct {
+	Start protoPosition
+	End   protoPosition
+}
+
+type protoPosition struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+func newProtoPosition(filename string, position protobuf.Position) protoPosition {
+	return protoPosition{
+		Filename: filename,
+		Line:     position.Line,
+		Column:   position.Column,
+	}
+}
+
+func (p protoPosition) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// protoFormatters holds the output formats of proto failures, the same ones
+// supported by revive.
+var protoFormatters = map[string]func(w io.Writer, failures []*protoFailure) error{
+	"default":    formatDefault,
+	"friendly":   formatFriendly,
+	"json":       formatJSON,
+	"ndjson":     formatNDJSON,
+	"stylish":    formatStylish,
+	"checkstyle": formatCheckstyle,
+}
+
+func formatDefault(w io.Writer, failures []*protoFailure) error {
+	for _, f := range failures {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", f.Position.Start, f.Failure); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatFriendly(w io.Writer, failures []*protoFailure) error {
+	for _, f := range failures {
+		if _, err := fmt.Fprintf(w, "  %s  %s  %s\n  %s\n\n", severityIcon(f.Severity), f.RuleName, f.Failure, f.Position.Start); err != nil {
+			return err
+		}
+	}
+
+	return writeSummary(w, failures)
+}
+
+func formatJSON(w io.Writer, failures []*protoFailure) error {
+	if failures == nil {
+		failures = []*protoFailure{}
+	}
+
+	b, err := json.Marshal(failures)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func formatNDJSON(w io.Writer, failures []*protoFailure) error {
+	encoder := json.NewEncoder(w)
+	for _, f := range failures {
+		if err := encoder.Encode(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatStylish(w io.Writer, failures []*protoFailure) error {
+	var filename string
+	for _, f := range failures {
+		if f.Position.Start.Filename != filename {
+			if filename != "" {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+
+			filename = f.Position.Start.Filename
+			if _, err := fmt.Fprintln(w, filename); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "  (%d, %d)  %s  %s\n", f.Position.Start.Line, f.Position.Start.Column, f.RuleName, f.Failure); err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return writeSummary(w, failures)
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name          `xml:"checkstyle"`
+	Version string            `xml:"version,attr"`
+	Files   []*checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string             `xml:"name,attr"`
+	Errors []*checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Message  string `xml:"message,attr"`
+	Severity string `xml:"severity,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func formatCheckstyle(w io.Writer, failures []*protoFailure) error {
+	report := &checkstyleReport{
+		Version: "5.0",
+	}
+
+	for _, f := range failures {
+		if n := len(report.Files); n == 0 || report.Files[n-1].Name != f.Position.Start.Filename {
+			report.Files = append(report.Files, &checkstyleFile{Name: f.Position.Start.Filename})
+		}
+
+		file := report.Files[len(report.Files)-1]
+		file.Errors = append(file.Errors, &checkstyleError{
+			Line:     f.Position.Start.Line,
+			Column:   f.Position.Start.Column,
+			Message:  f.Failure,
+			Severity: f.Severity,
+			Source:   "mikros/" + f.RuleName,
+		})
+	}
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, b)
+	return err
+}
+
+func writeSummary(w io.Writer, failures []*protoFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var errors int
+	for _, f := range failures {
+		if f.Severity == severityError {
+			errors++
+		}
+	}
+
+	icon := severityIcon(severityWarning)
+	if errors > 0 {
+		icon = severityIcon(severityError)
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s (%s, %s)\n", icon,
+		plural(len(failures), "problem"), plural(errors, "error"), plural(len(failures)-errors, "warning"))
+	return err
+}
+
+func severityIcon(severity string) string {
+	if severity == severityError {
+		return "✘"
+	}
+
+	return "⚠"
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -0,0 +1,193 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+const (
+	openapiMetadataOption  = "(openapi.metadata)"
+	openapiOperationOption = "(openapi.operation)"
+)
+
+// protoRule is a convention checked in protobuf files.
+type protoRule struct {
+	category string
+	check    func(p *protobuf.Proto, args map[string]string) []*ruleFailure
+}
+
+// ruleFailure is a convention not followed by a protobuf file.
+type ruleFailure struct {
+	message  string
+	position protobuf.Position
+}
+
+// protoRules holds the supported proto rules, by name.
+var protoRules = map[string]*protoRule{
+	"package-name":      {category: "naming", check: checkPackageName},
+	"go-package":        {category: "style", check: checkGoPackage},
+	"service-name":      {category: "naming", check: checkServiceName},
+	"api-file-name":     {category: "naming", check: checkAPIFileName},
+	"rpc-message-names": {category: "naming", check: checkRPCMessageNames},
+	"openapi-metadata":  {category: "documentation", check: checkOpenapiMetadata},
+	"openapi-operation": {category: "documentation", check: checkOpenapiOperation},
+}
+
+func failure(position protobuf.Position, format string, args ...interface{}) *ruleFailure {
+	return &ruleFailure{
+		message:  fmt.Sprintf(format, args...),
+		position: position,
+	}
+}
+
+// checkPackageName checks if the package is <project>.<service> and matches
+// the directory of the file, like <project>/<service>.
+func checkPackageName(p *protobuf.Proto, args map[string]string) []*ruleFailure {
+	if p.Package == "" {
+		return []*ruleFailure{failure(protobuf.Position{Line: 1, Column: 1}, "file should declare a package")}
+	}
+
+	var (
+		failures []*ruleFailure
+		parts    = strings.Split(p.Package, ".")
+		pkgPath  = strings.Join(parts, "/")
+		dir      = filepath.ToSlash(filepath.Dir(p.Filename))
+	)
+
+	if project := args["project"]; project != "" && (len(parts) != 2 || parts[0] != project) {
+		failures = append(failures, failure(p.PackagePosition, "package '%s' should be named %s.<service>", p.Package, project))
+	}
+	if dir != pkgPath && !strings.HasSuffix(dir, "/"+pkgPath) {
+		failures = append(failures, failure(p.PackagePosition, "package '%s' should match the file directory, like %s", p.Package, pkgPath))
+	}
+
+	return failures
+}
+
+// checkGoPackage checks if go_package is <prefix>/gen/go/<package path>;<service>.
+func checkGoPackage(p *protobuf.Proto, args map[string]string) []*ruleFailure {
+	if p.Package == "" {
+		return nil
+	}
+
+	var (
+		suffix   = "gen/go/" + strings.ReplaceAll(p.Package, ".", "/") + ";" + p.ServiceName
+		expected = "<prefix>/" + suffix
+	)
+	if prefix := args["prefix"]; prefix != "" {
+		expected = prefix + "/" + suffix
+	}
+
+	option, ok := p.Options.Get("go_package")
+	if !ok {
+		return []*ruleFailure{failure(p.PackagePosition, "file should set the go_package option as \"%s\"", expected)}
+	}
+
+	value := option.Value.Scalar
+	if prefix := args["prefix"]; (prefix != "" && value != expected) || !strings.HasSuffix(value, "/"+suffix) {
+		return []*ruleFailure{failure(option.Position, "go_package \"%s\" should be \"%s\"", value, expected)}
+	}
+
+	return nil
+}
+
+// checkServiceName checks if services are named after the package, like
+// <Service>Service.
+func checkServiceName(p *protobuf.Proto, _ map[string]string) []*ruleFailure {
+	var (
+		failures []*ruleFailure
+		expected = strcase.ToCamel(p.ServiceName) + "Service"
+	)
+
+	for _, svc := range p.Services {
+		if svc.Name != expected {
+			failures = append(failures, failure(svc.Position, "service '%s' should be named '%s'", svc.Name, expected))
+		}
+	}
+
+	return failures
+}
+
+// checkAPIFileName checks if services are declared inside <service>_api.proto
+// files.
+func checkAPIFileName(p *protobuf.Proto, _ map[string]string) []*ruleFailure {
+	if len(p.Services) == 0 || p.ServiceName == "" {
+		return nil
+	}
+
+	expected := strcase.ToSnake(p.ServiceName) + "_api.proto"
+	if filepath.Base(p.Filename) != expected {
+		return []*ruleFailure{failure(p.Services[0].Position, "services should be declared inside a file named '%s'", expected)}
+	}
+
+	return nil
+}
+
+// checkRPCMessageNames checks if RPCs use <Method>Request and
+// <Method>Response messages.
+func checkRPCMessageNames(p *protobuf.Proto, _ map[string]string) []*ruleFailure {
+	var failures []*ruleFailure
+	for _, m := range p.Methods() {
+		if name := lastSegment(m.InputName); name != m.Name+"Request" {
+			failures = append(failures, failure(m.Position, "request of RPC '%s' should be named '%sRequest'", m.Name, m.Name))
+		}
+		if name := lastSegment(m.OutputName); name != m.Name+"Response" {
+			failures = append(failures, failure(m.Position, "response of RPC '%s' should be named '%sResponse'", m.Name, m.Name))
+		}
+	}
+
+	return failures
+}
+
+// checkOpenapiMetadata checks if files with RPCs bound to HTTP set the
+// (openapi.metadata) option.
+func checkOpenapiMetadata(p *protobuf.Proto, _ map[string]string) []*ruleFailure {
+	if !hasHTTPMethods(p) {
+		return nil
+	}
+
+	if _, ok := p.Options.Get(openapiMetadataOption); !ok {
+		return []*ruleFailure{failure(p.PackagePosition, "files of HTTP services should set the %s option", openapiMetadataOption)}
+	}
+
+	return nil
+}
+
+// checkOpenapiOperation checks if RPCs bound to HTTP set the
+// (openapi.operation) option.
+func checkOpenapiOperation(p *protobuf.Proto, _ map[string]string) []*ruleFailure {
+	var failures []*ruleFailure
+	for _, m := range p.Methods() {
+		if m.HTTP == nil {
+			continue
+		}
+		if _, ok := m.Options.Get(openapiOperationOption); !ok {
+			failures = append(failures, failure(m.Position, "RPC '%s' should set the %s option", m.Name, openapiOperationOption))
+		}
+	}
+
+	return failures
+}
+
+func hasHTTPMethods(p *protobuf.Proto) bool {
+	for _, m := range p.Methods() {
+		if m.HTTP != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lastSegment(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
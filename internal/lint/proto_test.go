@@ -0,0 +1,218 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+const (
+	conformingProto = "testdata/proto/services/billing/billing_api.proto"
+	violatingProto  = "testdata/proto/services/orders/api.proto"
+)
+
+func testProfile() *settings.Profile {
+	profile := &settings.Profile{}
+	profile.Project.ProtobufMonorepo.ProjectName = "services"
+	profile.Project.ProtobufMonorepo.VcsPath = "github.com/acme"
+	profile.Project.ProtobufMonorepo.RepositoryName = "protobuf-workspace"
+
+	return profile
+}
+
+func lintProtoFile(t *testing.T, filename string, opts Options) []*protoFailure {
+	t.Helper()
+
+	config, err := loadProtoConfig(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := protobuf.Parse(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return applyProtoRules(p, config)
+}
+
+func failureNames(failures []*protoFailure) []string {
+	var names []string
+	for _, f := range failures {
+		names = append(names, fmt.Sprintf("%d %s %s", f.Position.Start.Line, f.Severity, f.RuleName))
+	}
+
+	return names
+}
+
+func TestProtoRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     []string
+	}{
+		{
+			name:     "conforming file",
+			filename: conformingProto,
+		},
+		{
+			name:     "violating file",
+			filename: violatingProto,
+			want: []string{
+				"3 warning openapi-metadata",
+				"3 error package-name",
+				"3 error package-name",
+				"7 error go-package",
+				"9 warning api-file-name",
+				"9 error service-name",
+				"10 warning openapi-operation",
+				"10 warning rpc-message-names",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failureNames(lintProtoFile(t, tt.filename, Options{Profile: testProfile()}))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got failures:\n%v\nwant:\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtoRulesCustomConfig(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "proto.toml")
+	content := `severity = "error"
+
+[rule.service-name]
+
+[rule.rpc-message-names]
+severity = "warning"
+
+[rule.openapi-operation]
+disabled = true
+`
+	if err := os.WriteFile(config, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := failureNames(lintProtoFile(t, violatingProto, Options{Config: config}))
+	want := []string{
+		"9 error service-name",
+		"10 warning rpc-message-names",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got failures:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestProtoRulesConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "unknown rule",
+			content: "[rule.field-names]\n",
+			err:     "unknown proto rule 'field-names'",
+		},
+		{
+			name:    "unsupported severity",
+			content: "[rule.service-name]\nseverity = \"fatal\"\n",
+			err:     "unsupported severity 'fatal' of proto rule 'service-name'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := filepath.Join(t.TempDir(), "proto.toml")
+			if err := os.WriteFile(config, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := loadProtoConfig(Options{Config: config}); err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFindProtoFiles(t *testing.T) {
+	got, err := findProtoFiles("testdata/...", []string{"testdata/proto/services/orders/..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{conformingProto}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+}
+
+func TestProtoFormatters(t *testing.T) {
+	failures := lintProtoFile(t, violatingProto, Options{Profile: testProfile()})
+
+	t.Run("default", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := formatDefault(&buf, failures); err != nil {
+			t.Fatal(err)
+		}
+
+		want := violatingProto + ":9:1: service 'Orders' should be named 'OrdersService'\n"
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := formatJSON(&buf, failures); err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded []map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if len(decoded) != len(failures) {
+			t.Fatalf("got %d failures, want %d", len(decoded), len(failures))
+		}
+		for _, key := range []string{"Severity", "Failure", "RuleName", "Category", "Position", "Confidence"} {
+			if _, ok := decoded[0][key]; !ok {
+				t.Errorf("failure does not have the %s field", key)
+			}
+		}
+	})
+
+	t.Run("checkstyle", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := formatCheckstyle(&buf, failures); err != nil {
+			t.Fatal(err)
+		}
+
+		want := `<error line="9" column="1" message="service &#39;Orders&#39; should be named &#39;OrdersService&#39;" severity="error" source="mikros/service-name"></error>`
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, buf.String())
+		}
+	})
+
+	t.Run("stylish", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := formatStylish(&buf, failures); err != nil {
+			t.Fatal(err)
+		}
+
+		if want := "✘ 8 problems (4 errors, 4 warnings)\n"; !strings.HasSuffix(buf.String(), want) {
+			t.Errorf("output does not end with %q:\n%s", want, buf.String())
+		}
+	})
+}
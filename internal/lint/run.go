@@ -13,6 +13,7 @@ import (
 
 	"github.com/mikros-dev/mikros-cli/internal/fs"
 	"github.com/mikros-dev/mikros-cli/internal/process"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
 )
 
 const (
@@ -27,6 +28,16 @@ type Options struct {
 	Config  string
 	Path    string `validate:"required"`
 	Exclude []string
+
+	// Interactive runs the linter inside a pseudo-terminal so its output
+	// keeps the terminal formatting.
+	Interactive bool
+
+	// Proto lints protobuf files with the mikros conventions instead of Go
+	// code. Conventions depending on the protobuf repository, like the
+	// go_package prefix, come from Profile.
+	Proto   bool
+	Profile *settings.Profile
 }
 
 // Run executes a linter for analyzing code style and issues.
@@ -38,6 +49,9 @@ func Run(ctx context.Context, opts Options) error {
 	if opts.Debug {
 		log.SetLevel(log.DebugLevel)
 	}
+	if opts.Proto {
+		return runProtoLint(opts)
+	}
 
 	if err := locateDependencies(); err != nil {
 		return err
@@ -61,13 +75,11 @@ func executeLint(ctx context.Context, opts Options) error {
 	}
 
 	// Set up the revive command call
-	configPath, err := saveReviveConfig(opts.Config, "default")
+	configPath, cleanup, err := saveReviveConfig(opts.Config, "default")
 	if err != nil {
 		return err
 	}
-	defer func() {
-		_ = os.Remove(configPath)
-	}()
+	defer cleanup()
 
 	args := []string{
 		tool,
@@ -88,7 +100,12 @@ func executeLint(ctx context.Context, opts Options) error {
 		log.Debug(args)
 	}
 
-	out, code, err := process.ExecWithPTY(ctx, args...)
+	exec := process.ExecContext
+	if opts.Interactive {
+		exec = process.ExecWithPTY
+	}
+
+	out, code, err := exec(ctx, args...)
 	if err != nil {
 		// We show lint errors for the user.
 		if code == lintErrorExitCode {
@@ -99,33 +116,42 @@ func executeLint(ctx context.Context, opts Options) error {
 		return fmt.Errorf("revive: %w - %s", err, string(out))
 	}
 
+	// Formats like json always have something to show
+	_, _ = os.Stdout.Write(out)
 	return nil
 }
 
-func saveReviveConfig(config, profile string) (string, error) {
+// saveReviveConfig returns the path of the revive configuration to use and
+// a function removing it once linting is done. A custom config belongs to
+// the user, so only the one generated from the profile is removed.
+func saveReviveConfig(config, profile string) (string, func(), error) {
 	if config != "" {
 		// User custom config path
-		return config, nil
+		return config, func() {}, nil
 	}
 
 	data, err := loadReviveConfig(profile)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	f, err := os.CreateTemp("", "revive-config-*.toml")
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	defer func() {
 		_ = f.Close()
 	}()
 
+	cleanup := func() {
+		_ = os.Remove(f.Name())
+	}
 	if _, err := f.Write(data); err != nil {
-		return "", err
+		cleanup()
+		return "", nil, err
 	}
 
-	return f.Name(), nil
+	return f.Name(), cleanup, nil
 }
 
 func loadReviveConfig(profile string) ([]byte, error) {
@@ -0,0 +1,293 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mikros-dev/mikros-cli/internal/template"
+	"github.com/mikros-dev/mikros-cli/internal/version"
+)
+
+const (
+	// Filename is the manifest path, relative to the generated project.
+	Filename = ".mikros/manifest.json"
+
+	// BaseDir is the directory, relative to the generated project, keeping
+	// a copy of the generated content of every file, as it was before any
+	// change made by users. It is the base used when merging upgrades.
+	BaseDir = ".mikros/base"
+
+	// OriginDefinitions is the origin of files generated from answers
+	// instead of templates, like the service.toml file.
+	OriginDefinitions = "definitions"
+
+	formatVersion = 1
+)
+
+// secretAnswers matches names of answers that must not be recorded.
+var secretAnswers = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private[_-]?key|api[_-]?key)`)
+
+// Manifest records what the CLI generated for a project, so that later
+// tooling can detect changes made in generated files and regenerate them.
+type Manifest struct {
+	FormatVersion int                    `json:"format_version"`
+	CLIVersion    string                 `json:"cli_version"`
+	Kind          string                 `json:"kind"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Plugins       []*Plugin              `json:"plugins,omitempty"`
+	Answers       map[string]interface{} `json:"answers,omitempty"`
+	Redacted      []string               `json:"redacted,omitempty"`
+	Files         []*File                `json:"files"`
+}
+
+// Plugin is a plugin used to generate the project.
+type Plugin struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Version  string `json:"version,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// File is a file generated for the project.
+type File struct {
+	// Path is the file path, relative to the project directory.
+	Path string `json:"path"`
+
+	// Template is the name of the template that generated the file.
+	Template string `json:"template,omitempty"`
+
+	// Origin is where the template came from: "builtin", the path of a
+	// custom template, a plugin or a template pack.
+	Origin string `json:"origin"`
+
+	// Hash is the checksum of the generated content.
+	Hash string `json:"hash"`
+
+	content []byte
+}
+
+// New creates a new Manifest for a project of the given kind.
+func New(kind string) *Manifest {
+	return &Manifest{
+		FormatVersion: formatVersion,
+		CLIVersion:    version.Get(),
+		Kind:          kind,
+		GeneratedAt:   time.Now().UTC(),
+	}
+}
+
+// Load loads the manifest of the project at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(path, Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// AddGenerated records a file generated from a template and written at
+// filename.
+func (m *Manifest) AddGenerated(filename string, gen *template.GeneratedTemplate) {
+	m.addFile(filename, gen.TemplateName(), gen.Origin(), gen.Content())
+}
+
+// AddFile records a file not generated from a template.
+func (m *Manifest) AddFile(filename, origin string, content []byte) {
+	m.addFile(filename, "", origin, content)
+}
+
+func (m *Manifest) addFile(filename, templateName, origin string, content []byte) {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+
+	m.Files = append(m.Files, &File{
+		Path:     filename,
+		Template: templateName,
+		Origin:   origin,
+		Hash:     Hash(content),
+		content:  content,
+	})
+}
+
+// AddPlugin records a plugin used to generate the project.
+func (m *Manifest) AddPlugin(p *Plugin) {
+	m.Plugins = append(m.Plugins, p)
+}
+
+// SetAnswers records the answers used to generate the project, leaving out
+// the ones that look like secrets. The names of those are recorded instead,
+// joined by '.' with the names of the answers containing them.
+func (m *Manifest) SetAnswers(answers map[string]interface{}) {
+	var redacted []string
+	m.Answers = withoutSecrets(answers, "", &redacted)
+
+	sort.Strings(redacted)
+	m.Redacted = slices.Compact(redacted)
+}
+
+// CheckAnswers checks if the recorded answers are enough to generate the
+// project again, i.e., if none of them was left out for being a secret.
+func (m *Manifest) CheckAnswers() error {
+	if len(m.Redacted) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("the project can't be generated again, since answers that look like secrets were not recorded: %s", strings.Join(m.Redacted, ", "))
+}
+
+func withoutSecrets(answers map[string]interface{}, prefix string, redacted *[]string) map[string]interface{} {
+	values := make(map[string]interface{}, len(answers))
+	for k, v := range answers {
+		name := prefix + k
+		if secretAnswers.MatchString(k) {
+			*redacted = append(*redacted, name)
+			continue
+		}
+
+		switch a := v.(type) {
+		case map[string]interface{}:
+			values[k] = withoutSecrets(a, name+".", redacted)
+
+		case []map[string]interface{}:
+			list := make([]map[string]interface{}, len(a))
+			for i, item := range a {
+				list[i] = withoutSecrets(item, name+".", redacted)
+			}
+			values[k] = list
+
+		case []interface{}:
+			list := make([]interface{}, len(a))
+			for i, item := range a {
+				if entry, ok := item.(map[string]interface{}); ok {
+					item = withoutSecrets(entry, name+".", redacted)
+				}
+				list[i] = item
+			}
+			values[k] = list
+
+		default:
+			values[k] = v
+		}
+	}
+
+	return values
+}
+
+// Write saves the manifest inside the project at path, along with the
+// generated content of its files. Files are recorded relative to it.
+func (m *Manifest) Write(path string) error {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range m.Files {
+		if rel, err := filepath.Rel(root, f.Path); err == nil && filepath.IsAbs(f.Path) {
+			f.Path = filepath.ToSlash(rel)
+		}
+	}
+
+	if err := writeBase(root, m.Files); err != nil {
+		return err
+	}
+
+	return m.save(root)
+}
+
+// Save saves only the manifest inside the project at path, leaving the
+// generated content of its files as it is.
+func (m *Manifest) Save(path string) error {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	return m.save(root)
+}
+
+func (m *Manifest) save(root string) error {
+	sort.Slice(m.Files, func(i, j int) bool {
+		return m.Files[i].Path < m.Files[j].Path
+	})
+
+	filename := filepath.Join(root, Filename)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, append(b, '\n'), 0644)
+}
+
+func writeBase(root string, files []*File) error {
+	// Leftovers from a previous generation must not survive.
+	if err := os.RemoveAll(filepath.Join(root, BaseDir)); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.content == nil {
+			continue
+		}
+
+		filename := filepath.Join(root, BaseDir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, f.content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Base returns the generated content of the file inside the project at
+// path, as it was when the manifest was written. It returns false when the
+// content is not available.
+func (f *File) Base(path string) ([]byte, bool) {
+	data, err := os.ReadFile(filepath.Join(path, BaseDir, filepath.FromSlash(f.Path)))
+	if err != nil || Hash(data) != f.Hash {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// File returns the manifest entry of the file at path, relative to the
+// project directory.
+func (m *Manifest) File(path string) (*File, bool) {
+	for _, f := range m.Files {
+		if f.Path == path {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+// Hash returns the checksum of a file content, as recorded in the manifest.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,52 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetAnswers(t *testing.T) {
+	m := New("test")
+	m.SetAnswers(map[string]interface{}{
+		"name":    "invoices",
+		"api_key": "key",
+		"service_answers": map[string]interface{}{
+			"database":    "postgres",
+			"db_password": "password",
+		},
+		"feature_answers": []interface{}{
+			map[string]interface{}{"name": "cache", "token": "a"},
+			map[string]interface{}{"name": "queue", "token": "b"},
+		},
+	})
+
+	wantAnswers := map[string]interface{}{
+		"name": "invoices",
+		"service_answers": map[string]interface{}{
+			"database": "postgres",
+		},
+		"feature_answers": []interface{}{
+			map[string]interface{}{"name": "cache"},
+			map[string]interface{}{"name": "queue"},
+		},
+	}
+	if !reflect.DeepEqual(m.Answers, wantAnswers) {
+		t.Errorf("got answers %v, want %v", m.Answers, wantAnswers)
+	}
+
+	wantRedacted := []string{"api_key", "feature_answers.token", "service_answers.db_password"}
+	if !reflect.DeepEqual(m.Redacted, wantRedacted) {
+		t.Errorf("got redacted answers %v, want %v", m.Redacted, wantRedacted)
+	}
+	if err := m.CheckAnswers(); err == nil {
+		t.Error("expected an error checking redacted answers")
+	}
+
+	m.SetAnswers(map[string]interface{}{"name": "invoices"})
+	if m.Redacted != nil {
+		t.Errorf("got redacted answers %v, want none", m.Redacted)
+	}
+	if err := m.CheckAnswers(); err != nil {
+		t.Error(err)
+	}
+}
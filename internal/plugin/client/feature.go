@@ -25,6 +25,7 @@ func NewFeature(path, name string) *Feature {
 
 func (f *Feature) exec(args ...string) (string, error) {
 	cmd := exec.Command(f.name, args...)
+	cmd.Env = handshakeEnv()
 
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -118,3 +119,24 @@ func (f *Feature) ValidateAnswers(answers map[string]interface{}) (map[string]in
 
 	return d.Answers, nil
 }
+
+// GetVersion returns the version of the feature plugin. Plugins built with older
+// SDK versions do not support it.
+func (f *Feature) GetVersion() (string, error) {
+	out, err := f.exec("-V")
+	if err != nil {
+		return "", err
+	}
+
+	d, err := wire.DecodePluginData(out)
+	if err != nil {
+		return "", err
+	}
+
+	return d.Version, nil
+}
+
+// Checksum returns the checksum of the feature plugin executable.
+func (f *Feature) Checksum() (string, error) {
+	return checksum(f.name)
+}
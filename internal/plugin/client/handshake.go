@@ -0,0 +1,14 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mikros-dev/mikros-cli/internal/plugin/wire"
+)
+
+// handshakeEnv returns the environment used to execute plugins, which
+// advertises the CLI capabilities to them.
+func handshakeEnv() []string {
+	return append(os.Environ(), fmt.Sprintf("%s=%d", wire.TemplateAPIVersionEnv, wire.TemplateAPIVersion))
+}
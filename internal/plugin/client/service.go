@@ -27,6 +27,7 @@ func NewService(path, name string) *Service {
 
 func (s *Service) exec(args ...string) (string, error) {
 	cmd := exec.Command(s.name, args...)
+	cmd.Env = handshakeEnv()
 
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -133,3 +134,24 @@ func (s *Service) GetTemplates(answers map[string]interface{}) (*template.Templa
 
 	return &t, nil
 }
+
+// GetVersion returns the version of the service plugin. Plugins built with older
+// SDK versions do not support it.
+func (s *Service) GetVersion() (string, error) {
+	out, err := s.exec("-V")
+	if err != nil {
+		return "", err
+	}
+
+	d, err := wire.DecodePluginData(out)
+	if err != nil {
+		return "", err
+	}
+
+	return d.Version, nil
+}
+
+// Checksum returns the checksum of the service plugin executable.
+func (s *Service) Checksum() (string, error) {
+	return checksum(s.name)
+}
@@ -68,6 +68,11 @@ func (e *Encoder) SetKind(kind string) {
 	e.Kind = kind
 }
 
+// SetVersion sets the plugin version.
+func (e *Encoder) SetVersion(version string) {
+	e.Version = version
+}
+
 // SetError sets the error of the plugin.
 func (e *Encoder) SetError(err error) {
 	e.Error = err.Error()
@@ -1,5 +1,10 @@
 package survey
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // Survey is a structure that a client uses to tell mikros CLI how to present
 // its survey for the user to answer questions.
 type Survey struct {
@@ -57,3 +62,35 @@ const (
 	PromptMultiline
 	PromptConfirm
 )
+
+var promptKindNames = map[string]PromptKind{
+	"input":        PromptInput,
+	"select":       PromptSelect,
+	"multi_select": PromptMultiSelect,
+	"multiline":    PromptMultiline,
+	"confirm":      PromptConfirm,
+}
+
+// UnmarshalJSON decodes a PromptKind from its numeric value or from its name
+// (input, select, multi_select, multiline or confirm), which is easier to use
+// in hand-written files.
+func (p *PromptKind) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var value int
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("invalid prompt kind: %s", data)
+		}
+
+		*p = PromptKind(value)
+		return nil
+	}
+
+	kind, ok := promptKindNames[name]
+	if !ok {
+		return fmt.Errorf("unsupported prompt kind '%s'", name)
+	}
+
+	*p = kind
+	return nil
+}
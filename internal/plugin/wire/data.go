@@ -11,6 +11,7 @@ type PluginData struct {
 	Name     string                 `json:"name,omitempty"`
 	UIName   string                 `json:"ui_name,omitempty"`
 	Kind     string                 `json:"kind,omitempty"`
+	Version  string                 `json:"version,omitempty"`
 	Survey   json.RawMessage        `json:"survey,omitempty"`
 	Answers  map[string]interface{} `json:"answers,omitempty"`
 	Template json.RawMessage        `json:"template,omitempty"`
@@ -0,0 +1,20 @@
+package wire
+
+const (
+	// TemplateAPIVersion is the version of the helper functions available to
+	// templates. It must be incremented every time functions are added or
+	// changed, so plugins can check what they can use.
+	//
+	// Version 1: toCamel, toSnake, toUpperSnake, toKebab, basename and
+	// templateName.
+	//
+	// Version 2: pluralize, singularize, lower, upper, title, join, split,
+	// default, coalesce, dict, list, indent, nindent, goIdent, quote, squote
+	// and protoToGo.
+	TemplateAPIVersion = 2
+
+	// TemplateAPIVersionEnv is the environment variable that the CLI uses to
+	// advertise, to plugins, the version of the helper functions available
+	// to their templates.
+	TemplateAPIVersionEnv = "MIKROS_TEMPLATE_API_VERSION"
+)
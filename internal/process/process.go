@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
@@ -53,3 +54,27 @@ func ExecWithPTY(ctx context.Context, args ...string) ([]byte, int, error) {
 
 	return buf.Bytes(), code, err
 }
+
+// ExecContext executes a command locally, bound to ctx, and captures its
+// standard output and exit code. Unlike ExecWithPTY, it does not require a
+// terminal.
+func ExecContext(ctx context.Context, args ...string) ([]byte, int, error) {
+	if len(args) == 0 {
+		return nil, -1, errors.New("can't execute a nil command")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		err = fmt.Errorf("%w: %s", err, exitErr.Stderr)
+	}
+
+	code := -1
+	if cmd.ProcessState != nil {
+		code = cmd.ProcessState.ExitCode()
+	}
+
+	return out, code, err
+}
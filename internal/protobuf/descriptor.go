@@ -0,0 +1,445 @@
+package protobuf
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	// Well-known types are resolved with the descriptors linked into the
+	// binary, since their files are usually not found inside proto roots.
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var scalarFieldTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+}
+
+// Descriptors builds protobuf reflection descriptors of the parsed files, so
+// their messages can be handled dynamically, without generated code.
+// Options are not kept. Types declared inside imports that were not found,
+// like google well-known types, are resolved with the descriptors linked
+// into the binary.
+func (r *Registry) Descriptors() (*protoregistry.Files, error) {
+	b := &descriptorBuilder{
+		registry: r,
+		files:    &protoregistry.Files{},
+		built:    make(map[string]bool),
+	}
+
+	for _, p := range r.Files() {
+		if err := b.build(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.files, nil
+}
+
+type descriptorBuilder struct {
+	registry *Registry
+	files    *protoregistry.Files
+	built    map[string]bool
+}
+
+// fileBuilder builds the descriptor of a single file, collecting the files
+// it depends on.
+type fileBuilder struct {
+	*descriptorBuilder
+	proto        *Proto
+	dependencies []string
+}
+
+func (b *descriptorBuilder) build(p *Proto) error {
+	if b.built[p.Name] {
+		return nil
+	}
+	b.built[p.Name] = true
+
+	syntax := p.Syntax
+	switch syntax {
+	case "":
+		syntax = "proto2"
+	case "proto2", "proto3":
+	default:
+		return fmt.Errorf("%s: unsupported syntax '%s'", p.Name, p.Syntax)
+	}
+
+	f := &fileBuilder{
+		descriptorBuilder: b,
+		proto:             p,
+	}
+
+	for _, imp := range p.Imports {
+		if dep, ok := b.registry.File(imp.Name); ok {
+			if err := b.build(dep); err != nil {
+				return err
+			}
+			f.addDependency(imp.Name)
+			continue
+		}
+		if dep, err := protoregistry.GlobalFiles.FindFileByPath(imp.Name); err == nil {
+			f.addGlobalDependency(dep)
+		}
+	}
+
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:   proto.String(p.Name),
+		Syntax: proto.String(syntax),
+	}
+	if p.Package != "" {
+		fd.Package = proto.String(p.Package)
+	}
+
+	for _, m := range p.Messages {
+		msg, err := f.message(m)
+		if err != nil {
+			return err
+		}
+		fd.MessageType = append(fd.MessageType, msg)
+	}
+	for _, e := range p.Enums {
+		fd.EnumType = append(fd.EnumType, enumDescriptor(e))
+	}
+	for _, s := range p.Services {
+		svc, err := f.service(s)
+		if err != nil {
+			return err
+		}
+		fd.Service = append(fd.Service, svc)
+	}
+	fd.Dependency = f.dependencies
+
+	desc, err := protodesc.NewFile(fd, b.files)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.Name, err)
+	}
+
+	return b.files.RegisterFile(desc)
+}
+
+func (f *fileBuilder) addDependency(name string) {
+	for _, d := range f.dependencies {
+		if d == name {
+			return
+		}
+	}
+
+	f.dependencies = append(f.dependencies, name)
+}
+
+// addGlobalDependency adds a file linked into the binary as dependency,
+// registering it, along with its own dependencies, if needed.
+func (f *fileBuilder) addGlobalDependency(fd protoreflect.FileDescriptor) {
+	f.registerGlobal(fd)
+	f.addDependency(fd.Path())
+}
+
+func (b *descriptorBuilder) registerGlobal(fd protoreflect.FileDescriptor) {
+	if _, err := b.files.FindFileByPath(fd.Path()); err == nil {
+		return
+	}
+
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		b.registerGlobal(imports.Get(i).FileDescriptor)
+	}
+
+	_ = b.files.RegisterFile(fd)
+}
+
+func (f *fileBuilder) message(m *Message) (*descriptorpb.DescriptorProto, error) {
+	msg := &descriptorpb.DescriptorProto{
+		Name: proto.String(m.Name),
+	}
+
+	var oneOfs []string
+	oneOfIndex := func(name string) int32 {
+		for i, o := range oneOfs {
+			if o == name {
+				return int32(i)
+			}
+		}
+
+		oneOfs = append(oneOfs, name)
+		return int32(len(oneOfs) - 1)
+	}
+
+	for _, field := range m.Fields {
+		fd, entry, err := f.field(m, field)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			msg.NestedType = append(msg.NestedType, entry)
+		}
+		if field.OneOf != "" {
+			fd.OneofIndex = proto.Int32(oneOfIndex(field.OneOf))
+		}
+		msg.Field = append(msg.Field, fd)
+	}
+
+	// Optional fields of proto3 files belong to synthetic oneofs, declared
+	// after the real ones.
+	for _, fd := range msg.Field {
+		if fd.GetProto3Optional() {
+			fd.OneofIndex = proto.Int32(int32(len(oneOfs)))
+			oneOfs = append(oneOfs, "_"+fd.GetName())
+		}
+	}
+	for _, name := range oneOfs {
+		msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(name)})
+	}
+
+	for _, nested := range m.Messages {
+		n, err := f.message(nested)
+		if err != nil {
+			return nil, err
+		}
+		msg.NestedType = append(msg.NestedType, n)
+	}
+	for _, e := range m.Enums {
+		msg.EnumType = append(msg.EnumType, enumDescriptor(e))
+	}
+
+	for _, rg := range m.Reserved.Ranges {
+		// Descriptor ranges are exclusive.
+		msg.ReservedRange = append(msg.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{
+			Start: proto.Int32(int32(rg.Start)),
+			End:   proto.Int32(int32(rg.End) + 1),
+		})
+	}
+	msg.ReservedName = append(msg.ReservedName, m.Reserved.Names...)
+
+	return msg, nil
+}
+
+// field returns the descriptor of a field and, for map fields, the
+// descriptor of the nested message holding their entries.
+func (f *fileBuilder) field(m *Message, field *Field) (*descriptorpb.FieldDescriptorProto, *descriptorpb.DescriptorProto, error) {
+	fd := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(field.Name),
+		Number: proto.Int32(int32(field.Number)),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+
+	switch {
+	case field.Repeated:
+		fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	case field.Required:
+		fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+	case field.Optional && f.proto.Syntax == "proto3":
+		fd.Proto3Optional = proto.Bool(true)
+	}
+
+	if !field.IsMap() {
+		if err := f.setFieldType(fd, m, field.Type, field.TypeKind, field.TypeName); err != nil {
+			return nil, nil, err
+		}
+
+		return fd, nil, nil
+	}
+
+	entryName := mapEntryName(field.Name)
+	entry := &descriptorpb.DescriptorProto{
+		Name: proto.String(entryName),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{
+				Name:   proto.String("key"),
+				Number: proto.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:   scalarFieldTypes[field.KeyType].Enum(),
+			},
+			{
+				Name:   proto.String("value"),
+				Number: proto.Int32(2),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			},
+		},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+	if err := f.setFieldType(entry.Field[1], m, field.Type, field.TypeKind, field.TypeName); err != nil {
+		return nil, nil, err
+	}
+
+	fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	fd.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	fd.TypeName = proto.String("." + m.FullName + "." + entryName)
+
+	return fd, entry, nil
+}
+
+func (f *fileBuilder) setFieldType(fd *descriptorpb.FieldDescriptorProto, m *Message, declared string, kind TypeKind, name string) error {
+	if t, ok := scalarFieldTypes[declared]; ok {
+		fd.Type = t.Enum()
+		return nil
+	}
+
+	if kind == TypeKindUnknown || name == "" {
+		d, ok := f.resolveGlobal(m.FullName, declared)
+		if !ok {
+			return fmt.Errorf("%s: unknown type '%s' of field '%s.%s'", f.proto.Name, declared, m.FullName, fd.GetName())
+		}
+
+		kind = TypeKindMessage
+		if _, isEnum := d.(protoreflect.EnumDescriptor); isEnum {
+			kind = TypeKindEnum
+		}
+		name = string(d.FullName())
+	}
+
+	fd.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	if kind == TypeKindEnum {
+		fd.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+	}
+	fd.TypeName = proto.String("." + name)
+
+	return nil
+}
+
+// resolveGlobal finds a type used inside scope among the descriptors linked
+// into the binary, following protobuf scoping rules, and adds its file as
+// dependency.
+func (f *fileBuilder) resolveGlobal(scope, name string) (protoreflect.Descriptor, bool) {
+	candidates := []string{strings.TrimPrefix(name, ".")}
+	if !strings.HasPrefix(name, ".") {
+		for s := scope; s != ""; {
+			candidates = append([]string{s + "." + name}, candidates...)
+			i := strings.LastIndex(s, ".")
+			if i == -1 {
+				break
+			}
+			s = s[:i]
+		}
+	}
+
+	for _, candidate := range candidates {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(candidate))
+		if err != nil {
+			continue
+		}
+
+		switch d.(type) {
+		case protoreflect.MessageDescriptor, protoreflect.EnumDescriptor:
+			f.addGlobalDependency(d.ParentFile())
+			return d, true
+		}
+	}
+
+	return nil, false
+}
+
+func (f *fileBuilder) service(s *Service) (*descriptorpb.ServiceDescriptorProto, error) {
+	svc := &descriptorpb.ServiceDescriptorProto{
+		Name: proto.String(s.Name),
+	}
+
+	for _, m := range s.Methods {
+		input, err := f.methodType(s, m, m.InputType, m.InputName)
+		if err != nil {
+			return nil, err
+		}
+		output, err := f.methodType(s, m, m.OutputType, m.OutputName)
+		if err != nil {
+			return nil, err
+		}
+
+		svc.Method = append(svc.Method, &descriptorpb.MethodDescriptorProto{
+			Name:            proto.String(m.Name),
+			InputType:       proto.String("." + input),
+			OutputType:      proto.String("." + output),
+			ClientStreaming: proto.Bool(m.ClientStreaming),
+			ServerStreaming: proto.Bool(m.ServerStreaming),
+		})
+	}
+
+	return svc, nil
+}
+
+func (f *fileBuilder) methodType(s *Service, m *Method, resolved, declared string) (string, error) {
+	if resolved != "" {
+		return resolved, nil
+	}
+
+	if d, ok := f.resolveGlobal(f.proto.Package, declared); ok {
+		if _, isMessage := d.(protoreflect.MessageDescriptor); isMessage {
+			return string(d.FullName()), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: unknown message '%s' of RPC '%s.%s'", f.proto.Name, declared, s.Name, m.Name)
+}
+
+func enumDescriptor(e *Enum) *descriptorpb.EnumDescriptorProto {
+	enum := &descriptorpb.EnumDescriptorProto{
+		Name: proto.String(e.Name),
+	}
+
+	for _, v := range e.Values {
+		enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{
+			Name:   proto.String(v.Name),
+			Number: proto.Int32(int32(v.Number)),
+		})
+	}
+	if e.Options.String("allow_alias") == "true" {
+		enum.Options = &descriptorpb.EnumOptions{AllowAlias: proto.Bool(true)}
+	}
+
+	for _, rg := range e.Reserved.Ranges {
+		// Unlike messages, enum ranges are inclusive.
+		enum.ReservedRange = append(enum.ReservedRange, &descriptorpb.EnumDescriptorProto_EnumReservedRange{
+			Start: proto.Int32(int32(rg.Start)),
+			End:   proto.Int32(int32(rg.End)),
+		})
+	}
+	enum.ReservedName = append(enum.ReservedName, e.Reserved.Names...)
+
+	return enum
+}
+
+// mapEntryName returns the name of the message holding the entries of a map
+// field, like protoc does: the field name in camel case followed by "Entry".
+func mapEntryName(field string) string {
+	var (
+		b     strings.Builder
+		upper = true
+	)
+
+	for _, c := range field {
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper && 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(c)
+	}
+
+	return b.String() + "Entry"
+}
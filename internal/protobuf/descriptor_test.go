@@ -0,0 +1,102 @@
+package protobuf
+
+import (
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestRegistryDescriptors(t *testing.T) {
+	var (
+		root     = filepath.Join("testdata")
+		registry = NewRegistry(root)
+	)
+
+	if _, err := registry.Load(filepath.Join(root, "acme", "billing", "billing_api.proto")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := registry.Descriptors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := files.FindDescriptorByName("acme.billing.BillingService")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	methods := d.(protoreflect.ServiceDescriptor).Methods()
+	if methods.Len() != 5 {
+		t.Fatalf("methods: got %d", methods.Len())
+	}
+	if m := methods.ByName("UploadItems"); !m.IsStreamingClient() || m.IsStreamingServer() || m.Input().FullName() != "acme.billing.Invoice.Item" {
+		t.Errorf("UploadItems: got input %s, streaming %v/%v", m.Input().FullName(), m.IsStreamingClient(), m.IsStreamingServer())
+	}
+	if m := methods.ByName("Sync"); !m.IsStreamingClient() || !m.IsStreamingServer() {
+		t.Errorf("Sync: got streaming %v/%v", m.IsStreamingClient(), m.IsStreamingServer())
+	}
+
+	d, err = files.FindDescriptorByName("acme.billing.Invoice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoice := d.(protoreflect.MessageDescriptor)
+
+	fields := invoice.Fields()
+	if f := fields.ByName("labels"); !f.IsMap() || f.MapKey().Kind() != protoreflect.StringKind {
+		t.Errorf("labels: got map %v", f.IsMap())
+	}
+	if f := fields.ByName("notes"); !f.HasPresence() || f.ContainingOneof() == nil || !f.ContainingOneof().IsSynthetic() {
+		t.Errorf("notes: got presence %v", f.HasPresence())
+	}
+	if f := fields.ByName("company_id"); f.ContainingOneof() == nil || f.ContainingOneof().Name() != "payer" {
+		t.Error("company_id: missing oneof")
+	}
+	if f := fields.ByName("status"); f.Enum() == nil || f.Enum().FullName() != "acme.billing.Invoice.Status" {
+		t.Error("status: wrong enum")
+	}
+	if f := fields.ByName("items"); f.Message().Fields().ByName("price").Message().FullName() != "acme.common.Money" {
+		t.Error("items: price type not resolved from import")
+	}
+
+	// Well-known types are resolved from descriptors linked into the binary.
+	if f := fields.ByName("created_at"); f.Message() == nil || f.Message().FullName() != "google.protobuf.Timestamp" {
+		t.Error("created_at: well-known type not resolved")
+	}
+
+	// Descriptors are able to handle messages without generated code.
+	msg := dynamicpb.NewMessage(invoice)
+	input := `{"id":"inv-1","items":[{"description":"pen","price":{"currency":"CURRENCY_EUR","units":"3"}}],"labels":{"a":"b"},"companyId":"acme","createdAt":"2025-01-02T03:04:05Z"}`
+	if err := protojson.Unmarshal([]byte(input), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := protojson.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := dynamicpb.NewMessage(invoice)
+	if err := protojson.Unmarshal(output, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Get(fields.ByName("company_id")).String(); got != "acme" {
+		t.Errorf("company_id: got %q", got)
+	}
+}
+
+func TestMapEntryName(t *testing.T) {
+	for name, want := range map[string]string{
+		"labels":       "LabelsEntry",
+		"item_prices":  "ItemPricesEntry",
+		"_private_map": "PrivateMapEntry",
+	} {
+		if got := mapEntryName(name); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
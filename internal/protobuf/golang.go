@@ -0,0 +1,163 @@
+package protobuf
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GoType is the Go type generated for a protobuf message or enum.
+type GoType struct {
+	ImportPath  string
+	PackageName string
+	Name        string
+}
+
+// wellKnownPackages holds the Go packages of google well-known types, which
+// are usually not found inside proto roots.
+var wellKnownPackages = map[string]string{
+	"google.protobuf.Any":         "anypb",
+	"google.protobuf.Duration":    "durationpb",
+	"google.protobuf.Empty":       "emptypb",
+	"google.protobuf.FieldMask":   "fieldmaskpb",
+	"google.protobuf.Struct":      "structpb",
+	"google.protobuf.Value":       "structpb",
+	"google.protobuf.ListValue":   "structpb",
+	"google.protobuf.NullValue":   "structpb",
+	"google.protobuf.Timestamp":   "timestamppb",
+	"google.protobuf.BoolValue":   "wrapperspb",
+	"google.protobuf.BytesValue":  "wrapperspb",
+	"google.protobuf.DoubleValue": "wrapperspb",
+	"google.protobuf.FloatValue":  "wrapperspb",
+	"google.protobuf.Int32Value":  "wrapperspb",
+	"google.protobuf.Int64Value":  "wrapperspb",
+	"google.protobuf.StringValue": "wrapperspb",
+	"google.protobuf.UInt32Value": "wrapperspb",
+	"google.protobuf.UInt64Value": "wrapperspb",
+}
+
+// GoPackageName returns the name of the Go package generated for the file.
+func (p *Proto) GoPackageName() string {
+	if option := p.Options.String("go_package"); option != "" {
+		importPath, name, ok := strings.Cut(option, ";")
+		if ok {
+			return name
+		}
+
+		return goSanitizedName(path.Base(importPath))
+	}
+
+	return goSanitizedName(p.Package)
+}
+
+// GoName returns the Go name generated for the message or enum, declared
+// inside the file, with the fully qualified name fullName.
+func (p *Proto) GoName(fullName string) string {
+	name := fullName
+	if p.Package != "" {
+		name = strings.TrimPrefix(fullName, p.Package+".")
+	}
+
+	return GoCamelCase(name)
+}
+
+// GoType returns the Go type generated for the message or enum with the
+// fully qualified name fullName.
+func (r *Registry) GoType(fullName string) (*GoType, bool) {
+	if pkg, ok := wellKnownPackages[fullName]; ok {
+		return &GoType{
+			ImportPath:  "google.golang.org/protobuf/types/known/" + pkg,
+			PackageName: pkg,
+			Name:        GoCamelCase(strings.TrimPrefix(fullName, "google.protobuf.")),
+		}, true
+	}
+
+	for _, p := range r.Files() {
+		_, isMessage := p.Message(fullName)
+		_, isEnum := p.Enum(fullName)
+		if isMessage || isEnum {
+			return &GoType{
+				ImportPath:  p.GoPackage,
+				PackageName: p.GoPackageName(),
+				Name:        p.GoName(fullName),
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// GoCamelCase converts a protobuf name to the Go name generated for it, the
+// same way protoc-gen-go does. Names of nested types are joined by '_'.
+func GoCamelCase(s string) string {
+	var b []byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skips '.' followed by a lower case letter.
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			// Leading underscores become 'X', so the name is exported.
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skips '_' followed by a lower case letter.
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+
+			// Keeps the following lower case letters.
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+
+	return string(b)
+}
+
+// ImportPaths returns the directories containing filename, nearest first.
+// They can be used as import paths when the proto root is unknown.
+func ImportPaths(filename string) []string {
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return []string{filepath.Dir(filename)}
+	}
+
+	var paths []string
+	for {
+		paths = append(paths, dir)
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return paths
+}
+
+func goSanitizedName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' {
+			return '_'
+		}
+
+		return r
+	}, s)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
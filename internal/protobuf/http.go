@@ -0,0 +1,79 @@
+package protobuf
+
+import (
+	"strings"
+)
+
+const httpOption = "(google.api.http)"
+
+var httpMethods = []string{"get", "put", "post", "delete", "patch"}
+
+// HTTPRule is the HTTP binding of a method, declared with the google.api.http
+// option.
+type HTTPRule struct {
+	// Method is the HTTP method, in upper case. Custom methods keep their
+	// names as declared.
+	Method       string
+	Path         string
+	Body         string
+	ResponseBody string
+
+	AdditionalBindings []*HTTPRule
+}
+
+func loadHTTPRule(options Options) *HTTPRule {
+	var rule *HTTPRule
+
+	for _, option := range options {
+		switch {
+		case option.Name == httpOption:
+			rule = newHTTPRule(option.Value)
+
+		case strings.HasPrefix(option.Name, httpOption+"."):
+			// Fields set one by one, like:
+			//  option (google.api.http).get = "/v1/users";
+			if rule == nil {
+				rule = &HTTPRule{}
+			}
+			rule.set(strings.TrimPrefix(option.Name, httpOption+"."), option.Value)
+		}
+	}
+
+	return rule
+}
+
+func newHTTPRule(value *Value) *HTTPRule {
+	rule := &HTTPRule{}
+	for _, field := range value.Fields {
+		rule.set(field.Name, field.Value)
+	}
+
+	return rule
+}
+
+func (r *HTTPRule) set(name string, value *Value) {
+	switch name {
+	case "body":
+		r.Body = value.Scalar
+	case "response_body":
+		r.ResponseBody = value.Scalar
+	case "custom":
+		r.Method = value.String("kind")
+		r.Path = value.String("path")
+	case "additional_bindings":
+		r.AdditionalBindings = append(r.AdditionalBindings, newHTTPRule(value))
+	default:
+		for _, method := range httpMethods {
+			if name == method {
+				r.Method = strings.ToUpper(method)
+				r.Path = value.Scalar
+			}
+		}
+	}
+}
+
+// HasBody returns true if the request body is mapped into the request
+// message.
+func (r *HTTPRule) HasBody() bool {
+	return r.Body != ""
+}
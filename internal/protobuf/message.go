@@ -0,0 +1,244 @@
+package protobuf
+
+import (
+	"slices"
+
+	protofile "github.com/emicklei/proto"
+)
+
+// TypeKind is the kind of the type of a field.
+type TypeKind string
+
+// Supported type kinds.
+const (
+	TypeKindScalar  TypeKind = "scalar"
+	TypeKindMessage TypeKind = "message"
+	TypeKindEnum    TypeKind = "enum"
+
+	// TypeKindUnknown is the kind of types not declared in any parsed file,
+	// like the ones of imports that were not found.
+	TypeKindUnknown TypeKind = "unknown"
+)
+
+var scalarTypes = []string{
+	"double", "float", "int32", "int64", "uint32", "uint64", "sint32",
+	"sint64", "fixed32", "fixed64", "sfixed32", "sfixed64", "bool",
+	"string", "bytes",
+}
+
+// Message represents a protobuf message.
+type Message struct {
+	Name string
+
+	// FullName is the fully qualified name of the message, including its
+	// package and parent messages, without the leading dot.
+	FullName string
+
+	Comment  string
+	Options  Options
+	Fields   []*Field
+	Messages []*Message
+	Enums    []*Enum
+	Reserved Reserved
+	Position Position
+}
+
+// Field represents a field of a protobuf message.
+type Field struct {
+	Name    string
+	Number  int
+	Comment string
+	Options Options
+
+	// Type is the field type as declared. For map fields, it is the type of
+	// the map values.
+	Type string
+
+	// TypeKind and TypeName are the kind and the fully qualified name of the
+	// field type, once it is resolved. Scalar types are their own names.
+	TypeKind TypeKind
+	TypeName string
+
+	Repeated bool
+	Optional bool
+	Required bool
+
+	// KeyType is the type of the map keys, for map fields.
+	KeyType string
+
+	// OneOf is the name of the oneof the field belongs to, if any.
+	OneOf string
+
+	Position Position
+}
+
+// Enum represents a protobuf enum.
+type Enum struct {
+	Name string
+
+	// FullName is the fully qualified name of the enum, including its
+	// package and parent messages, without the leading dot.
+	FullName string
+
+	Comment  string
+	Options  Options
+	Values   []*EnumValue
+	Reserved Reserved
+	Position Position
+}
+
+// EnumValue represents a value of a protobuf enum.
+type EnumValue struct {
+	Name     string
+	Number   int
+	Comment  string
+	Options  Options
+	Position Position
+}
+
+func loadMessage(m *protofile.Message, scope string) *Message {
+	msg := &Message{
+		Name:     m.Name,
+		FullName: qualifiedName(scope, m.Name),
+		Comment:  comment(m.Comment),
+		Position: position(m.Position),
+	}
+
+	for _, element := range m.Elements {
+		switch e := element.(type) {
+		case *protofile.Option:
+			msg.Options = append(msg.Options, loadOption(e))
+		case *protofile.NormalField:
+			f := loadField(e.Field)
+			f.Repeated = e.Repeated
+			f.Optional = e.Optional
+			f.Required = e.Required
+			msg.Fields = append(msg.Fields, f)
+		case *protofile.MapField:
+			f := loadField(e.Field)
+			f.KeyType = e.KeyType
+			msg.Fields = append(msg.Fields, f)
+		case *protofile.Oneof:
+			for _, oneOfElement := range e.Elements {
+				if field, ok := oneOfElement.(*protofile.OneOfField); ok {
+					f := loadField(field.Field)
+					f.OneOf = e.Name
+					msg.Fields = append(msg.Fields, f)
+				}
+			}
+		case *protofile.Message:
+			if !e.IsExtend {
+				msg.Messages = append(msg.Messages, loadMessage(e, msg.FullName))
+			}
+		case *protofile.Enum:
+			msg.Enums = append(msg.Enums, loadEnum(e, msg.FullName))
+		case *protofile.Reserved:
+			msg.Reserved.load(e, maxFieldNumber)
+		}
+	}
+
+	return msg
+}
+
+func loadField(f *protofile.Field) *Field {
+	return &Field{
+		Name:     f.Name,
+		Number:   f.Sequence,
+		Comment:  comment(f.Comment),
+		Options:  loadOptions(f.Options),
+		Type:     f.Type,
+		Position: position(f.Position),
+	}
+}
+
+func loadEnum(e *protofile.Enum, scope string) *Enum {
+	enum := &Enum{
+		Name:     e.Name,
+		FullName: qualifiedName(scope, e.Name),
+		Comment:  comment(e.Comment),
+		Position: position(e.Position),
+	}
+
+	for _, element := range e.Elements {
+		switch v := element.(type) {
+		case *protofile.Option:
+			enum.Options = append(enum.Options, loadOption(v))
+		case *protofile.EnumField:
+			value := &EnumValue{
+				Name:     v.Name,
+				Number:   v.Integer,
+				Comment:  comment(v.Comment),
+				Position: position(v.Position),
+			}
+			for _, valueElement := range v.Elements {
+				if o, ok := valueElement.(*protofile.Option); ok {
+					value.Options = append(value.Options, loadOption(o))
+				}
+			}
+			enum.Values = append(enum.Values, value)
+		case *protofile.Reserved:
+			enum.Reserved.load(v, maxEnumValueNumber)
+		}
+	}
+
+	return enum
+}
+
+// Field returns the field named name.
+func (m *Message) Field(name string) (*Field, bool) {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+// FieldByNumber returns the field with the number number.
+func (m *Message) FieldByNumber(number int) (*Field, bool) {
+	for _, f := range m.Fields {
+		if f.Number == number {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+// Value returns the value named name.
+func (e *Enum) Value(name string) (*EnumValue, bool) {
+	for _, v := range e.Values {
+		if v.Name == name {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// ValueByNumber returns the first value with the number number. Enums
+// allowing aliases may have more than one.
+func (e *Enum) ValueByNumber(number int) (*EnumValue, bool) {
+	for _, v := range e.Values {
+		if v.Number == number {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// IsMap returns true if the field is a map.
+func (f *Field) IsMap() bool {
+	return f.KeyType != ""
+}
+
+// IsScalar returns true if the field type is a scalar type.
+func (f *Field) IsScalar() bool {
+	return isScalarType(f.Type)
+}
+
+func isScalarType(name string) bool {
+	return slices.Contains(scalarTypes, name)
+}
@@ -4,17 +4,108 @@ import (
 	protofile "github.com/emicklei/proto"
 )
 
+// StreamingMode is how messages are exchanged by an RPC.
+type StreamingMode string
+
+// Supported streaming modes.
+const (
+	StreamingModeUnary  StreamingMode = "unary"
+	StreamingModeClient StreamingMode = "client"
+	StreamingModeServer StreamingMode = "server"
+	StreamingModeBidi   StreamingMode = "bidi"
+)
+
+// Service represents a protobuf service.
+type Service struct {
+	Name     string
+	Comment  string
+	Options  Options
+	Methods  []*Method
+	Position Position
+}
+
 // Method represents a method of a protobuf service.
 type Method struct {
 	Name       string
 	InputName  string
 	OutputName string
+	Comment    string
+
+	// InputType and OutputType are the fully qualified names of the request
+	// and response messages, once they are resolved.
+	InputType  string
+	OutputType string
+
+	// ClientStreaming and ServerStreaming tell if the request and the
+	// response, respectively, are streams.
+	ClientStreaming bool
+	ServerStreaming bool
+
+	Options Options
+
+	// HTTP is the HTTP binding of the method, from the google.api.http
+	// option, if any.
+	HTTP *HTTPRule
+
+	Position Position
+}
+
+func loadService(s *protofile.Service) *Service {
+	svc := &Service{
+		Name:     s.Name,
+		Comment:  comment(s.Comment),
+		Position: position(s.Position),
+	}
+
+	for _, element := range s.Elements {
+		switch e := element.(type) {
+		case *protofile.Option:
+			svc.Options = append(svc.Options, loadOption(e))
+		case *protofile.RPC:
+			svc.Methods = append(svc.Methods, loadMethod(e))
+		}
+	}
+
+	return svc
 }
 
 func loadMethod(r *protofile.RPC) *Method {
-	return &Method{
-		Name:       r.Name,
-		InputName:  r.RequestType,
-		OutputName: r.ReturnsType,
+	m := &Method{
+		Name:            r.Name,
+		InputName:       r.RequestType,
+		OutputName:      r.ReturnsType,
+		Comment:         comment(r.Comment),
+		ClientStreaming: r.StreamsRequest,
+		ServerStreaming: r.StreamsReturns,
+		Position:        position(r.Position),
+	}
+
+	for _, element := range r.Elements {
+		if o, ok := element.(*protofile.Option); ok {
+			m.Options = append(m.Options, loadOption(o))
+		}
+	}
+	m.HTTP = loadHTTPRule(m.Options)
+
+	return m
+}
+
+// StreamingMode returns how messages are exchanged by the method.
+func (m *Method) StreamingMode() StreamingMode {
+	switch {
+	case m.ClientStreaming && m.ServerStreaming:
+		return StreamingModeBidi
+	case m.ClientStreaming:
+		return StreamingModeClient
+	case m.ServerStreaming:
+		return StreamingModeServer
 	}
+
+	return StreamingModeUnary
+}
+
+// IsStreaming returns true if the request or the response of the method is
+// a stream.
+func (m *Method) IsStreaming() bool {
+	return m.ClientStreaming || m.ServerStreaming
 }
@@ -0,0 +1,113 @@
+package protobuf
+
+import (
+	protofile "github.com/emicklei/proto"
+)
+
+// Option represents a protobuf option. Custom options keep their names as
+// declared, with parentheses, like "(google.api.http)".
+type Option struct {
+	Name     string
+	Value    *Value
+	Position Position
+}
+
+// Value is an option value, which can be a scalar, a list of values or an
+// aggregate, i.e., a message literal with named values.
+type Value struct {
+	// Scalar is the value of scalar literals. Strings are unquoted, while
+	// other literals (numbers, booleans and enum values) are kept as
+	// declared.
+	Scalar   string
+	IsString bool
+
+	List   []*Value
+	Fields []*NamedValue
+}
+
+// NamedValue is a value of an aggregate.
+type NamedValue struct {
+	Name  string
+	Value *Value
+}
+
+// Options holds options of a protobuf element.
+type Options []*Option
+
+func loadOptions(options []*protofile.Option) Options {
+	var o Options
+	for _, option := range options {
+		o = append(o, loadOption(option))
+	}
+
+	return o
+}
+
+func loadOption(o *protofile.Option) *Option {
+	return &Option{
+		Name:     o.Name,
+		Value:    loadValue(&o.Constant),
+		Position: position(o.Position),
+	}
+}
+
+func loadValue(l *protofile.Literal) *Value {
+	v := &Value{
+		Scalar:   l.Source,
+		IsString: l.IsString,
+	}
+
+	for _, item := range l.Array {
+		v.List = append(v.List, loadValue(item))
+	}
+	for _, field := range l.OrderedMap {
+		v.Fields = append(v.Fields, &NamedValue{
+			Name:  field.Name,
+			Value: loadValue(field.Literal),
+		})
+	}
+
+	return v
+}
+
+// Get returns the option named name.
+func (o Options) Get(name string) (*Option, bool) {
+	for _, option := range o {
+		if option.Name == name {
+			return option, true
+		}
+	}
+
+	return nil, false
+}
+
+// String returns the scalar value of the option named name, or an empty
+// string if it does not exist.
+func (o Options) String(name string) string {
+	if option, ok := o.Get(name); ok {
+		return option.Value.Scalar
+	}
+
+	return ""
+}
+
+// Get returns the aggregate value named name.
+func (v *Value) Get(name string) (*Value, bool) {
+	for _, f := range v.Fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// String returns the scalar value of the aggregate value named name, or an
+// empty string if it does not exist.
+func (v *Value) String(name string) string {
+	if value, ok := v.Get(name); ok {
+		return value.Scalar
+	}
+
+	return ""
+}
@@ -3,19 +3,78 @@ package protobuf
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"text/scanner"
 
 	protofile "github.com/emicklei/proto"
 )
 
 // Proto represents a protobuf file.
 type Proto struct {
+	// Filename is the path of the file.
+	Filename string
+
+	// Name is the file name used by imports, i.e., its path relative to the
+	// import path where it was found. Files parsed directly use their base
+	// names.
+	Name string
+
+	Syntax  string
+	Package string
+
+	// PackagePosition is where the package is declared.
+	PackagePosition Position
+
+	// ServiceName is the last segment of the package name.
 	ServiceName string
-	Methods     []*Method
+
+	// GoPackage is the import path of the generated Go package, from the
+	// go_package option, if set.
+	GoPackage string
+
+	Imports  []*Import
+	Options  Options
+	Services []*Service
+	Messages []*Message
+	Enums    []*Enum
+}
+
+// Position is the location of an element inside its file.
+type Position struct {
+	Line   int
+	Column int
 }
 
-// Parse parses a protobuf file.
+func position(p scanner.Position) Position {
+	return Position{
+		Line:   p.Line,
+		Column: p.Column,
+	}
+}
+
+// Import is a file imported by a protobuf file.
+type Import struct {
+	Name   string
+	Public bool
+	Weak   bool
+}
+
+// Parse parses a protobuf file. Its imports are not parsed, so only types
+// declared inside the file itself are resolved.
 func Parse(filename string) (*Proto, error) {
+	p, err := parseFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Name = filepath.Base(filename)
+	resolveTypes(p, []*Proto{p})
+
+	return p, nil
+}
+
+func parseFile(filename string) (*Proto, error) {
 	reader, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open %s: %w", filename, err)
@@ -25,29 +84,60 @@ func Parse(filename string) (*Proto, error) {
 	}(reader)
 
 	parser := protofile.NewParser(reader)
+	parser.Filename(filename)
+
 	definitions, err := parser.Parse()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
 	}
 
-	p := &Proto{}
+	p := &Proto{
+		Filename: filename,
+	}
 	p.parse(definitions)
 
 	return p, nil
 }
 
 func (p *Proto) parse(definitions *protofile.Proto) {
-	protofile.Walk(definitions,
-		protofile.WithPackage(p.parsePackage),
-		protofile.WithRPC(p.parseMethods))
-}
+	// The package is needed to build the full names of messages and enums,
+	// which may be declared before it.
+	for _, element := range definitions.Elements {
+		if pkg, ok := element.(*protofile.Package); ok {
+			p.parsePackage(pkg)
+		}
+	}
 
-func (p *Proto) parseMethods(r *protofile.RPC) {
-	m := loadMethod(r)
-	p.Methods = append(p.Methods, m)
+	for _, element := range definitions.Elements {
+		switch e := element.(type) {
+		case *protofile.Syntax:
+			p.Syntax = e.Value
+		case *protofile.Edition:
+			p.Syntax = "editions"
+		case *protofile.Import:
+			p.Imports = append(p.Imports, &Import{
+				Name:   e.Filename,
+				Public: e.Kind == "public",
+				Weak:   e.Kind == "weak",
+			})
+		case *protofile.Option:
+			p.parseOption(e)
+		case *protofile.Service:
+			p.Services = append(p.Services, loadService(e))
+		case *protofile.Message:
+			if !e.IsExtend {
+				p.Messages = append(p.Messages, loadMessage(e, p.Package))
+			}
+		case *protofile.Enum:
+			p.Enums = append(p.Enums, loadEnum(e, p.Package))
+		}
+	}
 }
 
 func (p *Proto) parsePackage(pkg *protofile.Package) {
+	p.Package = pkg.Name
+	p.PackagePosition = position(pkg.Position)
+
 	name := pkg.Name
 	if strings.Contains(name, ".") {
 		parts := strings.Split(name, ".")
@@ -55,3 +145,107 @@ func (p *Proto) parsePackage(pkg *protofile.Package) {
 	}
 	p.ServiceName = name
 }
+
+func (p *Proto) parseOption(o *protofile.Option) {
+	option := loadOption(o)
+	p.Options = append(p.Options, option)
+
+	if option.Name == "go_package" {
+		// The package name may follow the import path, separated by ';'.
+		importPath, _, _ := strings.Cut(option.Value.Scalar, ";")
+		p.GoPackage = importPath
+	}
+}
+
+// Methods returns the methods of all services of the file.
+func (p *Proto) Methods() []*Method {
+	var methods []*Method
+	for _, s := range p.Services {
+		methods = append(methods, s.Methods...)
+	}
+
+	return methods
+}
+
+// Service returns the service named name.
+func (p *Proto) Service(name string) (*Service, bool) {
+	for _, s := range p.Services {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
+// Message returns the message, declared inside the file, with the fully
+// qualified name fullName (without the leading dot).
+func (p *Proto) Message(fullName string) (*Message, bool) {
+	return findMessage(p.Messages, fullName)
+}
+
+// Enum returns the enum, declared inside the file, with the fully qualified
+// name fullName (without the leading dot).
+func (p *Proto) Enum(fullName string) (*Enum, bool) {
+	for _, e := range p.Enums {
+		if e.FullName == fullName {
+			return e, true
+		}
+	}
+
+	return findNestedEnum(p.Messages, fullName)
+}
+
+func findMessage(messages []*Message, fullName string) (*Message, bool) {
+	for _, m := range messages {
+		if m.FullName == fullName {
+			return m, true
+		}
+		if strings.HasPrefix(fullName, m.FullName+".") {
+			if nested, ok := findMessage(m.Messages, fullName); ok {
+				return nested, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func findNestedEnum(messages []*Message, fullName string) (*Enum, bool) {
+	for _, m := range messages {
+		if !strings.HasPrefix(fullName, m.FullName+".") {
+			continue
+		}
+		for _, e := range m.Enums {
+			if e.FullName == fullName {
+				return e, true
+			}
+		}
+		if e, ok := findNestedEnum(m.Messages, fullName); ok {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
+
+func comment(c *protofile.Comment) string {
+	if c == nil {
+		return ""
+	}
+
+	lines := make([]string, len(c.Lines))
+	for i, line := range c.Lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func qualifiedName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+
+	return scope + "." + name
+}
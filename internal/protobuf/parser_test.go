@@ -0,0 +1,179 @@
+package protobuf
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryLoad(t *testing.T) {
+	var (
+		root     = filepath.Join("testdata")
+		registry = NewRegistry(root)
+	)
+
+	p, err := registry.Load(filepath.Join(root, "acme", "billing", "billing_api.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("file", func(t *testing.T) {
+		if p.Name != "acme/billing/billing_api.proto" {
+			t.Errorf("name: got %q", p.Name)
+		}
+		if p.Package != "acme.billing" || p.ServiceName != "billing" {
+			t.Errorf("package: got %q (%q)", p.Package, p.ServiceName)
+		}
+		if p.GoPackage != "github.com/acme/protos/gen/go/acme/billing" {
+			t.Errorf("go_package: got %q", p.GoPackage)
+		}
+		if len(p.Imports) != 2 {
+			t.Errorf("imports: got %d", len(p.Imports))
+		}
+
+		// Only imports found inside the proto root are loaded.
+		var names []string
+		for _, f := range registry.Files() {
+			names = append(names, f.Name)
+		}
+		if want := []string{"acme/billing/billing_api.proto", "acme/common/money.proto"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("files: got %v, want %v", names, want)
+		}
+	})
+
+	t.Run("services", func(t *testing.T) {
+		if len(p.Services) != 2 || len(p.Methods()) != 6 {
+			t.Fatalf("got %d services with %d methods", len(p.Services), len(p.Methods()))
+		}
+
+		svc, ok := p.Service("BillingService")
+		if !ok {
+			t.Fatal("BillingService not found")
+		}
+		if svc.Comment != "BillingService handles invoices." {
+			t.Errorf("comment: got %q", svc.Comment)
+		}
+		if want := (Position{Line: 11, Column: 1}); svc.Position != want || p.PackagePosition.Line != 3 {
+			t.Errorf("positions: got %+v (package %+v)", svc.Position, p.PackagePosition)
+		}
+		if want := (Position{Line: 13, Column: 3}); svc.Methods[0].Position != want {
+			t.Errorf("GetInvoice position: got %+v", svc.Methods[0].Position)
+		}
+
+		modes := map[string]StreamingMode{
+			"GetInvoice":    StreamingModeUnary,
+			"WatchInvoices": StreamingModeServer,
+			"UploadItems":   StreamingModeClient,
+			"Sync":          StreamingModeBidi,
+		}
+		for _, m := range svc.Methods {
+			if mode, ok := modes[m.Name]; ok && m.StreamingMode() != mode {
+				t.Errorf("%s: got %s streaming, want %s", m.Name, m.StreamingMode(), mode)
+			}
+		}
+
+		upload := svc.Methods[3]
+		if upload.InputName != "Invoice.Item" || upload.InputType != "acme.billing.Invoice.Item" {
+			t.Errorf("UploadItems input: got %q (%q)", upload.InputName, upload.InputType)
+		}
+	})
+
+	t.Run("http", func(t *testing.T) {
+		svc, _ := p.Service("BillingService")
+
+		get := svc.Methods[0].HTTP
+		if get == nil || get.Method != "GET" || get.Path != "/v1/invoices/{id}" || get.HasBody() {
+			t.Fatalf("GetInvoice: got %+v", get)
+		}
+		if len(get.AdditionalBindings) != 1 || get.AdditionalBindings[0].Path != "/v1/customers/{customer_id}/invoices/{id}" {
+			t.Errorf("GetInvoice additional bindings: got %+v", get.AdditionalBindings)
+		}
+
+		create := svc.Methods[1].HTTP
+		if create == nil || create.Method != "POST" || create.Body != "*" {
+			t.Errorf("CreateInvoice: got %+v", create)
+		}
+		if svc.Methods[2].HTTP != nil {
+			t.Errorf("WatchInvoices: got %+v", svc.Methods[2].HTTP)
+		}
+
+		admin, _ := p.Service("BillingAdminService")
+		purge := admin.Methods[0].HTTP
+		if purge == nil || purge.Method != "DELETE" || purge.Path != "/v1/invoices" {
+			t.Errorf("PurgeInvoices: got %+v", purge)
+		}
+	})
+
+	t.Run("messages", func(t *testing.T) {
+		invoice, ok := p.Message("acme.billing.Invoice")
+		if !ok {
+			t.Fatal("Invoice not found")
+		}
+
+		tests := []struct {
+			name  string
+			check func(f *Field) bool
+		}{
+			{"id", func(f *Field) bool { return f.Number == 1 && f.TypeKind == TypeKindScalar }},
+			{"items", func(f *Field) bool {
+				return f.Repeated && f.TypeKind == TypeKindMessage && f.TypeName == "acme.billing.Invoice.Item"
+			}},
+			{"status", func(f *Field) bool { return f.TypeKind == TypeKindEnum && f.TypeName == "acme.billing.Invoice.Status" }},
+			{"labels", func(f *Field) bool { return f.IsMap() && f.KeyType == "string" && f.Type == "string" }},
+			{"notes", func(f *Field) bool { return f.Optional && f.Options.String("deprecated") == "true" }},
+			{"customer_id", func(f *Field) bool { return f.OneOf == "payer" }},
+			{"created_at", func(f *Field) bool { return f.TypeKind == TypeKindUnknown }},
+		}
+		for _, tt := range tests {
+			f, ok := invoice.Field(tt.name)
+			if !ok {
+				t.Errorf("%s: not found", tt.name)
+				continue
+			}
+			if !tt.check(f) {
+				t.Errorf("%s: unexpected field %+v", tt.name, f)
+			}
+		}
+
+		// Types are resolved across imports.
+		item, ok := registry.Message("acme.billing.Invoice.Item")
+		if !ok {
+			t.Fatal("Invoice.Item not found")
+		}
+		if price, _ := item.Field("price"); price.TypeKind != TypeKindMessage || price.TypeName != "acme.common.Money" {
+			t.Errorf("price: got %+v", price)
+		}
+
+		req, _ := p.Message("acme.billing.CreateInvoiceRequest")
+		if currency, _ := req.Field("currency"); currency.TypeKind != TypeKindEnum || currency.TypeName != "acme.common.Currency" {
+			t.Errorf("currency: got %+v", currency)
+		}
+	})
+
+	t.Run("enums", func(t *testing.T) {
+		currency, ok := registry.Enum("acme.common.Currency")
+		if !ok {
+			t.Fatal("Currency not found")
+		}
+		if len(currency.Values) != 3 || currency.Values[2].Name != "CURRENCY_EUR" || currency.Values[2].Number != 2 {
+			t.Errorf("got %+v", currency.Values)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse(filepath.Join("testdata", "acme", "billing", "billing_api.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Name != "billing_api.proto" {
+		t.Errorf("name: got %q", p.Name)
+	}
+
+	// Imports are not parsed, so their types are not resolved.
+	item, _ := p.Message("acme.billing.Invoice.Item")
+	if price, _ := item.Field("price"); price.TypeKind != TypeKindUnknown {
+		t.Errorf("price: got %+v", price)
+	}
+}
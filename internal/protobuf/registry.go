@@ -0,0 +1,248 @@
+package protobuf
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+)
+
+// Registry holds protobuf files parsed along with the files they import,
+// which are searched inside a list of import paths (proto roots).
+type Registry struct {
+	importPaths []string
+	files       map[string]*Proto
+	names       []string
+}
+
+// NewRegistry creates a registry that searches imported files inside
+// importPaths, in order.
+func NewRegistry(importPaths ...string) *Registry {
+	return &Registry{
+		importPaths: importPaths,
+		files:       make(map[string]*Proto),
+	}
+}
+
+// Load parses a protobuf file and, recursively, the files it imports, and
+// resolves the types they use. Imports not found inside the import paths,
+// like google well-known types, are skipped and their types are left
+// unresolved.
+func (r *Registry) Load(filename string) (*Proto, error) {
+	p, err := r.load(filename, r.importName(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range r.names {
+		file := r.files[name]
+		resolveTypes(file, r.visibleFiles(file))
+	}
+
+	return p, nil
+}
+
+func (r *Registry) load(filename, name string) (*Proto, error) {
+	if p, ok := r.files[name]; ok {
+		return p, nil
+	}
+
+	p, err := parseFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	p.Name = name
+
+	r.files[name] = p
+	r.names = append(r.names, name)
+
+	for _, imp := range p.Imports {
+		importFilename, ok := r.find(imp.Name)
+		if !ok {
+			continue
+		}
+		if _, err := r.load(importFilename, imp.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// importName returns the name used to import filename, i.e., its path
+// relative to the import path containing it.
+func (r *Registry) importName(filename string) string {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filepath.Base(filename)
+	}
+
+	for _, importPath := range r.importPaths {
+		root, err := filepath.Abs(importPath)
+		if err != nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(root, abs)
+		if err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	return filepath.Base(filename)
+}
+
+func (r *Registry) find(name string) (string, bool) {
+	for _, importPath := range r.importPaths {
+		filename := filepath.Join(importPath, filepath.FromSlash(name))
+		if fs.FindPath(filename) {
+			return filename, true
+		}
+	}
+
+	return "", false
+}
+
+// visibleFiles returns the files whose types can be used by p: the file
+// itself, its imports and, recursively, their public imports.
+func (r *Registry) visibleFiles(p *Proto) []*Proto {
+	var (
+		files = []*Proto{p}
+		seen  = map[string]bool{p.Name: true}
+	)
+
+	var add func(imports []*Import)
+	add = func(imports []*Import) {
+		for _, imp := range imports {
+			file, ok := r.files[imp.Name]
+			if !ok || seen[imp.Name] {
+				continue
+			}
+
+			seen[imp.Name] = true
+			files = append(files, file)
+
+			var public []*Import
+			for _, i := range file.Imports {
+				if i.Public {
+					public = append(public, i)
+				}
+			}
+			add(public)
+		}
+	}
+	add(p.Imports)
+
+	return files
+}
+
+// File returns the file imported as name.
+func (r *Registry) File(name string) (*Proto, bool) {
+	p, ok := r.files[name]
+	return p, ok
+}
+
+// Files returns all parsed files, in the order they were loaded.
+func (r *Registry) Files() []*Proto {
+	files := make([]*Proto, len(r.names))
+	for i, name := range r.names {
+		files[i] = r.files[name]
+	}
+
+	return files
+}
+
+// Message returns the message with the fully qualified name fullName
+// (without the leading dot) declared in any parsed file.
+func (r *Registry) Message(fullName string) (*Message, bool) {
+	for _, p := range r.Files() {
+		if m, ok := p.Message(fullName); ok {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// Enum returns the enum with the fully qualified name fullName (without the
+// leading dot) declared in any parsed file.
+func (r *Registry) Enum(fullName string) (*Enum, bool) {
+	for _, p := range r.Files() {
+		if e, ok := p.Enum(fullName); ok {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
+
+// resolveTypes resolves the types of fields and methods of p with the types
+// declared inside files.
+func resolveTypes(p *Proto, files []*Proto) {
+	var resolveMessages func(messages []*Message)
+	resolveMessages = func(messages []*Message) {
+		for _, m := range messages {
+			for _, f := range m.Fields {
+				if f.IsScalar() {
+					f.TypeKind = TypeKindScalar
+					f.TypeName = f.Type
+					continue
+				}
+
+				f.TypeKind, f.TypeName = resolveType(files, m.FullName, f.Type)
+			}
+			resolveMessages(m.Messages)
+		}
+	}
+	resolveMessages(p.Messages)
+
+	for _, s := range p.Services {
+		for _, m := range s.Methods {
+			if kind, name := resolveType(files, p.Package, m.InputName); kind == TypeKindMessage {
+				m.InputType = name
+			}
+			if kind, name := resolveType(files, p.Package, m.OutputName); kind == TypeKindMessage {
+				m.OutputType = name
+			}
+		}
+	}
+}
+
+// resolveType finds the fully qualified name of a type used inside scope,
+// following protobuf scoping rules: the innermost scope is searched first.
+func resolveType(files []*Proto, scope, name string) (TypeKind, string) {
+	if strings.HasPrefix(name, ".") {
+		return lookupType(files, name[1:])
+	}
+
+	for {
+		if kind, fullName := lookupType(files, qualifiedName(scope, name)); kind != TypeKindUnknown {
+			return kind, fullName
+		}
+		if scope == "" {
+			break
+		}
+
+		i := strings.LastIndex(scope, ".")
+		if i == -1 {
+			scope = ""
+			continue
+		}
+		scope = scope[:i]
+	}
+
+	return TypeKindUnknown, ""
+}
+
+func lookupType(files []*Proto, fullName string) (TypeKind, string) {
+	for _, p := range files {
+		if _, ok := p.Message(fullName); ok {
+			return TypeKindMessage, fullName
+		}
+		if _, ok := p.Enum(fullName); ok {
+			return TypeKindEnum, fullName
+		}
+	}
+
+	return TypeKindUnknown, ""
+}
@@ -0,0 +1,109 @@
+package protobuf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	protofile "github.com/emicklei/proto"
+)
+
+// Highest numbers of fields and enum values, used by ranges ending at max.
+const (
+	maxFieldNumber     = 536870911
+	maxEnumValueNumber = 2147483647
+)
+
+// Reserved holds the numbers and names that can't be used by the fields of
+// a message or the values of an enum.
+type Reserved struct {
+	Ranges []*Range
+	Names  []string
+}
+
+// Range is an inclusive range of numbers.
+type Range struct {
+	Start int
+	End   int
+}
+
+func (r *Reserved) load(reserved *protofile.Reserved, maxNumber int) {
+	for _, rg := range reserved.Ranges {
+		end := rg.To
+		if rg.Max {
+			end = maxNumber
+		}
+
+		r.Ranges = append(r.Ranges, &Range{
+			Start: rg.From,
+			End:   end,
+		})
+	}
+
+	r.Names = append(r.Names, reserved.FieldNames...)
+}
+
+// HasNumber returns true if number is reserved.
+func (r *Reserved) HasNumber(number int) bool {
+	for _, rg := range r.Ranges {
+		if rg.Contains(number) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasName returns true if name is reserved.
+func (r *Reserved) HasName(name string) bool {
+	return slices.Contains(r.Names, name)
+}
+
+// Covers returns true if every number of rg is reserved.
+func (r *Reserved) Covers(rg *Range) bool {
+	for number := rg.Start; number <= rg.End; {
+		next, ok := r.rangeEnd(number)
+		if !ok {
+			return false
+		}
+		if next >= rg.End {
+			return true
+		}
+		number = next + 1
+	}
+
+	return true
+}
+
+// rangeEnd returns the end of the reserved range containing number.
+func (r *Reserved) rangeEnd(number int) (int, bool) {
+	var (
+		end   int
+		found bool
+	)
+	for _, rg := range r.Ranges {
+		if rg.Contains(number) && (!found || rg.End > end) {
+			end = rg.End
+			found = true
+		}
+	}
+
+	return end, found
+}
+
+// Contains returns true if number is inside the range.
+func (r *Range) Contains(number int) bool {
+	return number >= r.Start && number <= r.End
+}
+
+// String returns the range as it is declared.
+func (r *Range) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	if r.End == maxFieldNumber || r.End == maxEnumValueNumber {
+		return fmt.Sprintf("%d to max", r.Start)
+	}
+
+	return fmt.Sprintf("%d to %d", r.Start, r.End)
+}
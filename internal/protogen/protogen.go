@@ -0,0 +1,313 @@
+package protogen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+	"github.com/mikros-dev/mikros-cli/internal/git"
+	"github.com/mikros-dev/mikros-cli/internal/process"
+)
+
+const (
+	protoDir = "proto"
+	genDir   = "gen"
+)
+
+// Options holds options for generating code from the protobuf files of a
+// protobuf repository. When none of Service, Changed and Files are set,
+// every service is generated.
+type Options struct {
+	// Path is a directory inside the protobuf repository. It defaults to
+	// the current directory.
+	Path string
+
+	// Service is the service to generate, by name (like "billing") or by
+	// its directory inside the proto directory (like "services/billing").
+	Service string
+
+	// Changed is a git reference. Only services whose files, or files they
+	// import, changed since it are generated.
+	Changed string
+
+	// Files are protobuf files, relative to the proto directory, that
+	// changed. Only services affected by them are generated.
+	Files []string
+
+	// Jobs is the number of services generated at the same time. It
+	// defaults to the number of CPUs.
+	Jobs int
+
+	// Update updates the dependencies of the generated Go modules.
+	Update bool
+
+	// Check builds the generated Go modules, including mocks and test
+	// modules, failing when they don't compile.
+	Check bool
+}
+
+// Result is the result of a generation.
+type Result struct {
+	Root     string           `json:"root"`
+	Tools    []*Tool          `json:"tools"`
+	Warnings []string         `json:"warnings,omitempty"`
+	Services []*ServiceResult `json:"services"`
+	Modules  []string         `json:"modules,omitempty"`
+	Updated  bool             `json:"updated,omitempty"`
+	Checked  bool             `json:"checked,omitempty"`
+}
+
+// ServiceResult is the generation result of a service.
+type ServiceResult struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Failed returns the services that could not be generated.
+func (r *Result) Failed() []*ServiceResult {
+	var failed []*ServiceResult
+	for _, s := range r.Services {
+		if s.Error != "" {
+			failed = append(failed, s)
+		}
+	}
+
+	return failed
+}
+
+// Generate generates code, using buf, and mocks of gRPC APIs for the
+// services of the protobuf repository containing the current directory.
+// Services are generated in parallel and failures of one of them do not
+// stop the others, being reported in the result.
+func Generate(ctx context.Context, options *Options) (*Result, error) {
+	if options.Service != "" && options.Changed != "" {
+		return nil, errors.New("a service and a git reference can't be used together")
+	}
+
+	root, err := RepositoryRoot(ctx, options.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generation tools use paths relative to the repository root.
+	cwd, err := fs.ChangeDir(root)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Chdir(cwd)
+	}()
+
+	tools, warnings, err := checkTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := selectServices(ctx, root, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Root:     root,
+		Tools:    tools,
+		Warnings: warnings,
+		Services: generateServices(ctx, services, options),
+		Updated:  options.Update,
+		Checked:  options.Check,
+	}
+
+	// The gen/go module is shared by every service, so it is kept tidy only
+	// after all of them were generated.
+	if len(services) > 0 && fs.FindPath(filepath.Join(genDir, "go", "go.mod")) {
+		module := filepath.Join(genDir, "go")
+		if err := prepareModule(ctx, module, options); err != nil {
+			return nil, err
+		}
+		result.Modules = append(result.Modules, filepath.ToSlash(module))
+	}
+
+	return result, nil
+}
+
+// RepositoryRoot returns the root path of the protobuf repository containing
+// path or, when empty, the current directory.
+func RepositoryRoot(ctx context.Context, path string) (string, error) {
+	var root string
+	if path == "" {
+		repo, err := git.LoadFromCwd()
+		if err != nil {
+			return "", err
+		}
+		if !repo.IsValidRepository() {
+			return "", errors.New("current directory is not inside a protobuf repository")
+		}
+		root = repo.RootPath
+	} else {
+		r, err := git.TopLevel(ctx, path)
+		if err != nil {
+			return "", err
+		}
+		root = r
+	}
+
+	for _, name := range []string{"buf.gen.yaml", protoDir} {
+		if !fs.FindPath(filepath.Join(root, name)) {
+			return "", fmt.Errorf("'%s' is not a protobuf repository: %s not found", root, name)
+		}
+	}
+
+	return root, nil
+}
+
+func generateServices(ctx context.Context, services []string, options *Options) []*ServiceResult {
+	jobs := options.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	var (
+		results = make([]*ServiceResult, len(services))
+		sem     = make(chan struct{}, jobs)
+		wg      sync.WaitGroup
+	)
+
+	for i, service := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+
+			start := time.Now()
+			results[i] = &ServiceResult{Name: service}
+			if err := generateService(ctx, service, options); err != nil {
+				results[i].Error = strings.TrimSpace(err.Error())
+			}
+			results[i].Duration = time.Since(start).Round(time.Millisecond)
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// generateService replaces the previously generated files of a service,
+// generating its code, mocks of its gRPC APIs and keeping its generated
+// modules tidy.
+func generateService(ctx context.Context, service string, options *Options) error {
+	outputs, err := outputDirs(service)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range outputs {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+
+	if _, _, err := process.ExecContext(ctx, "buf", "generate", protoDir, "--path", filepath.Join(protoDir, service)); err != nil {
+		return fmt.Errorf("buf generate: %w", err)
+	}
+
+	if err := generateMocks(ctx, service); err != nil {
+		return err
+	}
+
+	for _, dir := range outputs {
+		if !fs.FindPath(filepath.Join(dir, "go.mod")) {
+			continue
+		}
+		if err := prepareModule(ctx, dir, options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// outputDirs returns the directories where files of a service are generated,
+// one for each language (or kind) inside the gen directory, like
+// gen/go/services/billing.
+func outputDirs(service string) ([]string, error) {
+	entries, err := os.ReadDir(genDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{filepath.Join(genDir, "go", service), filepath.Join(genDir, "mock", service)}, nil
+		}
+
+		return nil, err
+	}
+
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, filepath.Join(genDir, e.Name(), service))
+		}
+	}
+
+	return dirs, nil
+}
+
+// generateMocks generates mocks of the gRPC APIs of a service inside
+// gen/mock, like gen/mock/services/billing/billing_api_grpc_mock.go.
+func generateMocks(ctx context.Context, service string) error {
+	source := filepath.Join(genDir, "go", service)
+	if !fs.FindPath(source) {
+		return nil
+	}
+
+	return filepath.WalkDir(source, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, "_api_grpc.pb.go") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(filepath.Join(genDir, "go"), path)
+		if err != nil {
+			return err
+		}
+
+		destination := filepath.Join(genDir, "mock", strings.TrimSuffix(rel, ".pb.go")+"_mock.go")
+		if _, _, err := process.ExecContext(ctx, "mockgen", "-source", path, "-destination", destination); err != nil {
+			return fmt.Errorf("mockgen %s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// prepareModule keeps a generated Go module tidy, updating its dependencies
+// before and building it after, when requested.
+func prepareModule(ctx context.Context, dir string, options *Options) error {
+	commands := [][]string{{"mod", "tidy"}}
+	if options.Update {
+		commands = slices.Insert(commands, 0, []string{"get", "-u", "./..."})
+	}
+	if options.Check {
+		commands = append(commands, []string{"build", "./..."})
+	}
+
+	for _, args := range commands {
+		if _, _, err := process.ExecContext(ctx, append([]string{"go", "-C", dir}, args...)...); err != nil {
+			return fmt.Errorf("go %s %s: %w", strings.Join(args, " "), filepath.ToSlash(dir), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package protogen
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeTools holds scripts replacing the tools used to generate code. buf
+// generates a gRPC file for every service, except services/orders, which
+// fails.
+var fakeTools = map[string]string{
+	"buf": `#!/bin/sh
+if [ "$1" = "--version" ]; then echo 1.49.0; exit 0; fi
+service=${4#proto/}
+if [ "$service" = "services/orders" ]; then echo "orders_api.proto:1:1: syntax error" >&2; exit 1; fi
+mkdir -p gen/go/$service && touch gen/go/$service/$(basename $service)_api_grpc.pb.go
+`,
+	"mockgen": `#!/bin/sh
+mkdir -p $(dirname $4) && touch $4
+`,
+	"go": `#!/bin/sh
+case "$3" in
+mod) touch $2/tidy ;;
+get) touch $2/updated ;;
+build) touch $2/built ;;
+esac
+`,
+	"protoc-gen-mikros-extensions": `#!/bin/sh
+`,
+}
+
+// setupRepository copies the test repository into a git repository, with
+// the fake tools in the PATH, and switches to it.
+func setupRepository(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tools are shell scripts")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	var (
+		repo = t.TempDir()
+		bin  = t.TempDir()
+	)
+
+	if err := os.CopyFS(repo, os.DirFS(filepath.Join("testdata", "repo"))); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, script := range fakeTools {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@test", "commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	t.Chdir(repo)
+	return repo
+}
+
+func TestFindServices(t *testing.T) {
+	services, err := FindServices(filepath.Join("testdata", "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"common/money", "legacy/orders", "services/billing", "services/orders"}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("got services %v, want %v", services, want)
+	}
+
+	tests := []struct {
+		name string
+		want string
+		err  string
+	}{
+		{name: "billing", want: "services/billing"},
+		{name: "legacy/orders", want: "legacy/orders"},
+		{name: "orders", err: "service name 'orders' is ambiguous, use one of: legacy/orders, services/orders"},
+		{name: "payments", err: "service 'payments' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findService(services, tt.name)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %q (%v), want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAffectedServices(t *testing.T) {
+	var (
+		root        = filepath.Join("testdata", "repo")
+		services, _ = FindServices(root)
+	)
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "imported file",
+			files: []string{"common/money/money.proto"},
+			want:  []string{"common/money", "services/billing"},
+		},
+		{
+			name:  "service file",
+			files: []string{"services/orders/orders_api.proto"},
+			want:  []string{"services/orders"},
+		},
+		{
+			name:  "removed file",
+			files: []string{"legacy/orders/removed.proto"},
+			want:  []string{"legacy/orders"},
+		},
+		{
+			name: "no changes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AffectedServices(root, services, tt.files)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got services %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	repo := setupRepository(t)
+
+	stale := filepath.Join(repo, "gen", "go", "services", "billing", "stale.pb.go")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range []string{stale, filepath.Join(repo, "gen", "go", "go.mod")} {
+		if err := os.WriteFile(filename, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := Generate(context.Background(), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range result.Services {
+		got = append(got, strings.TrimSpace(s.Name+" "+s.Error))
+	}
+	want := []string{
+		"common/money",
+		"legacy/orders",
+		"services/billing",
+		"services/orders buf generate: exit status 1: orders_api.proto:1:1: syntax error",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got services:\n%v\nwant:\n%v", got, want)
+	}
+	if len(result.Failed()) != 1 {
+		t.Errorf("got %d failed services", len(result.Failed()))
+	}
+	if !reflect.DeepEqual(result.Modules, []string{"gen/go"}) {
+		t.Errorf("got tidy modules %v", result.Modules)
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("previously generated files were not removed")
+	}
+	for _, filename := range []string{
+		"gen/go/services/billing/billing_api_grpc.pb.go",
+		"gen/mock/services/billing/billing_api_grpc_mock.go",
+		"gen/go/tidy",
+	} {
+		if _, err := os.Stat(filepath.Join(repo, filename)); err != nil {
+			t.Errorf("%s was not generated: %v", filename, err)
+		}
+	}
+}
+
+func TestGenerateChanged(t *testing.T) {
+	repo := setupRepository(t)
+
+	filename := filepath.Join(repo, "proto", "common", "money", "money.proto")
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, append(content, "\nmessage Rate {}\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Generate(context.Background(), &Options{Changed: "HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range result.Services {
+		got = append(got, s.Name)
+	}
+	if want := []string{"common/money", "services/billing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got services %v, want %v", got, want)
+	}
+
+	// Changed files can also be given directly, like when watching them.
+	result, err = Generate(context.Background(), &Options{Path: repo, Files: []string{"services/billing/billing_api.proto"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Services) != 1 || result.Services[0].Name != "services/billing" {
+		t.Errorf("got services %+v", result.Services)
+	}
+
+	if _, err := Generate(context.Background(), &Options{Changed: "unknown"}); err == nil {
+		t.Error("expected an error for an unknown git reference")
+	}
+}
+
+func TestGenerateUpdateCheck(t *testing.T) {
+	repo := setupRepository(t)
+
+	module := filepath.Join(repo, "gen", "go")
+	if err := os.MkdirAll(module, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Generate(context.Background(), &Options{Service: "billing", Update: true, Check: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Updated || !result.Checked {
+		t.Errorf("got result %+v", result)
+	}
+
+	for _, name := range []string{"updated", "tidy", "built"} {
+		if _, err := os.Stat(filepath.Join(module, name)); err != nil {
+			t.Errorf("module was not %s: %v", name, err)
+		}
+	}
+}
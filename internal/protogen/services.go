@@ -0,0 +1,186 @@
+package protogen
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mikros-dev/mikros-cli/internal/git"
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+// FindServices returns the services of the proto directory of a protobuf
+// repository, i.e., the directories with protobuf files, relative to it and
+// using slashes, like "services/billing".
+func FindServices(root string) ([]string, error) {
+	var (
+		protoPath = filepath.Join(root, protoDir)
+		services  []string
+	)
+
+	err := filepath.WalkDir(protoPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != protoPath && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || filepath.Ext(p) != ".proto" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(protoPath, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); rel != "." && !slices.Contains(services, rel) {
+			services = append(services, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(services)
+	return services, nil
+}
+
+func selectServices(ctx context.Context, root string, options *Options) ([]string, error) {
+	services, err := FindServices(root)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case options.Service != "":
+		service, err := findService(services, options.Service)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{service}, nil
+
+	case options.Changed != "":
+		changed, err := git.ChangedFiles(ctx, root, options.Changed, protoDir)
+		if err != nil {
+			return nil, err
+		}
+
+		var files []string
+		for _, name := range changed {
+			files = append(files, strings.TrimPrefix(name, protoDir+"/"))
+		}
+
+		return AffectedServices(root, services, files)
+
+	case len(options.Files) > 0:
+		return AffectedServices(root, services, options.Files)
+	}
+
+	return services, nil
+}
+
+// findService returns the service named name, which can be its directory
+// or only its last element.
+func findService(services []string, name string) (string, error) {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+
+	var found []string
+	for _, s := range services {
+		if s == name {
+			return s, nil
+		}
+		if path.Base(s) == name {
+			found = append(found, s)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("service '%s' not found", name)
+	case 1:
+		return found[0], nil
+	}
+
+	return "", fmt.Errorf("service name '%s' is ambiguous, use one of: %s", name, strings.Join(found, ", "))
+}
+
+// AffectedServices returns the services affected by changes of files,
+// relative to the proto directory: services declaring them or importing
+// them, directly or not.
+func AffectedServices(root string, services, files []string) ([]string, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	registry := protobuf.NewRegistry(filepath.Join(root, protoDir))
+
+	var affected []string
+	for _, service := range services {
+		names, err := serviceFiles(registry, root, service)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if path.Dir(file) == service || slices.Contains(names, file) {
+				affected = append(affected, service)
+				break
+			}
+		}
+	}
+
+	return affected, nil
+}
+
+// serviceFiles returns the files of a service along with every file they
+// import, directly or not, found inside the proto directory.
+func serviceFiles(registry *protobuf.Registry, root, service string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, protoDir, filepath.FromSlash(service)))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		names []string
+		visit func(name string)
+	)
+
+	visit = func(name string) {
+		if slices.Contains(names, name) {
+			return
+		}
+		names = append(names, name)
+
+		if p, ok := registry.File(name); ok {
+			for _, imp := range p.Imports {
+				visit(imp.Name)
+			}
+		}
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".proto" {
+			continue
+		}
+
+		p, err := registry.Load(filepath.Join(root, protoDir, filepath.FromSlash(service), e.Name()))
+		if err != nil {
+			// Files that can't be parsed, like ones being edited, only
+			// affect their own service, whose generation reports the
+			// error.
+			continue
+		}
+		visit(p.Name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,157 @@
+package protogen
+
+import (
+	"context"
+	"debug/buildinfo"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+	"github.com/mikros-dev/mikros-cli/internal/process"
+)
+
+// minBufVersion is the first buf version supporting v2 configuration files.
+const minBufVersion = "v1.32.0"
+
+// Tool is an executable used to generate code.
+type Tool struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+// bufGenConfig is the part of buf.gen.yaml describing plugins.
+type bufGenConfig struct {
+	Plugins []struct {
+		// Local is the plugin executable, or a list with it and its
+		// arguments.
+		Local interface{} `yaml:"local"`
+	} `yaml:"plugins"`
+}
+
+// checkTools checks if the tools used to generate code are installed, along
+// with the local protoc plugins of buf.gen.yaml. Plugins whose versions are
+// not the ones required by the generated Go module are reported as
+// warnings.
+func checkTools(ctx context.Context) ([]*Tool, []string, error) {
+	buf, err := findTool("buf")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out, _, err := process.ExecContext(ctx, "buf", "--version")
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get the buf version: %w", err)
+	}
+	buf.Version = strings.TrimSpace(string(out))
+	if v := "v" + strings.TrimPrefix(buf.Version, "v"); !semver.IsValid(v) || semver.Compare(v, minBufVersion) < 0 {
+		return nil, nil, fmt.Errorf("buf %s is not supported, at least %s is required", buf.Version, minBufVersion)
+	}
+
+	tools := []*Tool{buf}
+	for _, name := range []string{"mockgen", "go"} {
+		tool, err := findTool(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		tools = append(tools, tool)
+	}
+
+	plugins, err := localPlugins()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	for _, name := range plugins {
+		tool, err := findTool(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		tools = append(tools, tool)
+
+		if warning := checkPluginVersion(tool); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return tools, warnings, nil
+}
+
+func findTool(name string) (*Tool, error) {
+	path, err := fs.FindBinary(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found, install it with 'make setup'", name)
+	}
+
+	tool := &Tool{
+		Name: name,
+		Path: path,
+	}
+	if info, err := buildinfo.ReadFile(path); err == nil && info.Main.Version != "(devel)" {
+		tool.Version = info.Main.Version
+	}
+
+	return tool, nil
+}
+
+// localPlugins returns the local plugins of buf.gen.yaml.
+func localPlugins() ([]string, error) {
+	data, err := os.ReadFile("buf.gen.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var config bufGenConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not parse buf.gen.yaml: %w", err)
+	}
+
+	var plugins []string
+	for _, p := range config.Plugins {
+		switch local := p.Local.(type) {
+		case string:
+			plugins = append(plugins, local)
+		case []interface{}:
+			if len(local) > 0 {
+				plugins = append(plugins, fmt.Sprint(local[0]))
+			}
+		}
+	}
+
+	return plugins, nil
+}
+
+// checkPluginVersion compares the version of a plugin with the version of
+// its module required by the generated Go module, since generated code
+// depends on it.
+func checkPluginVersion(tool *Tool) string {
+	info, err := buildinfo.ReadFile(tool.Path)
+	if err != nil || tool.Version == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(genDir, "go", "go.mod"))
+	if err != nil {
+		return ""
+	}
+
+	mod, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return ""
+	}
+
+	for _, r := range mod.Require {
+		if r.Mod.Path == info.Main.Path && r.Mod.Version != tool.Version {
+			return fmt.Sprintf("%s %s is installed, but the generated code requires %s", tool.Name, tool.Version, r.Mod.Version)
+		}
+	}
+
+	return ""
+}
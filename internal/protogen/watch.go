@@ -0,0 +1,136 @@
+package protogen
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long Watch waits, after a change, for other changes
+// before handling them.
+const DefaultDebounce = 300 * time.Millisecond
+
+// Watch watches the protobuf files of the proto directory of the repository
+// at root until ctx is done. Changes are debounced and handled in batches:
+// handle receives the changed files, relative to the proto directory and
+// using slashes.
+func Watch(ctx context.Context, root string, debounce time.Duration, handle func(files []string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	protoPath := filepath.Join(root, protoDir)
+	if _, err := watchDirs(watcher, protoPath); err != nil {
+		return err
+	}
+
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	var (
+		timer   = time.NewTimer(debounce)
+		pending = make(map[string]bool)
+	)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// New directories must be watched too, since watches are not
+			// recursive. Files they already have, like when they are moved
+			// into the proto directory, are changes as well.
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if strings.HasPrefix(info.Name(), ".") {
+						continue
+					}
+
+					// Directories removed right after being created are
+					// ignored.
+					files, err := watchDirs(watcher, event.Name)
+					if err != nil && !errors.Is(err, fs.ErrNotExist) {
+						return err
+					}
+					for _, name := range files {
+						addPending(pending, protoPath, name)
+					}
+					if len(files) > 0 {
+						timer.Reset(debounce)
+					}
+					continue
+				}
+			}
+			if filepath.Ext(event.Name) != ".proto" || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			addPending(pending, protoPath, event.Name)
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			files := make([]string, 0, len(pending))
+			for name := range pending {
+				files = append(files, name)
+			}
+			sort.Strings(files)
+			clear(pending)
+
+			handle(files)
+		}
+	}
+}
+
+// watchDirs adds dir and its subdirectories, except hidden ones, to the
+// watcher. It returns the protobuf files found inside them.
+func watchDirs(watcher *fsnotify.Watcher, dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			if filepath.Ext(path) == ".proto" {
+				files = append(files, path)
+			}
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		return watcher.Add(path)
+	})
+
+	return files, err
+}
+
+func addPending(pending map[string]bool, protoPath, filename string) {
+	if rel, err := filepath.Rel(protoPath, filename); err == nil {
+		pending[filepath.ToSlash(rel)] = true
+	}
+}
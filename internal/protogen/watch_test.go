@@ -0,0 +1,77 @@
+package protogen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		protoPath = filepath.Join(root, protoDir)
+		batches   = make(chan []string, 10)
+		errs      = make(chan error, 1)
+	)
+
+	if err := os.MkdirAll(filepath.Join(protoPath, "services", "billing"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		errs <- Watch(ctx, root, 100*time.Millisecond, func(files []string) {
+			batches <- files
+		})
+	}()
+
+	// Gives the watcher some time to start watching directories.
+	time.Sleep(100 * time.Millisecond)
+
+	write := func(name string) {
+		filename := filepath.Join(protoPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(`syntax = "proto3";`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	next := func() []string {
+		select {
+		case files := <-batches:
+			return files
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for changes")
+		}
+
+		return nil
+	}
+
+	// Changes close to each other are handled together, ignoring files
+	// that are not protobuf files.
+	write("services/billing/billing_api.proto")
+	write("services/billing/billing.proto")
+	write("services/billing/README.md")
+
+	if got, want := next(), []string{"services/billing/billing.proto", "services/billing/billing_api.proto"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+
+	// New directories are watched as well.
+	write("services/orders/orders_api.proto")
+	if got, want := next(), []string{"services/orders/orders_api.proto"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,139 @@
+package rpc
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+// openapiMetadataOption is the file option set by http-spec services.
+const openapiMetadataOption = "(openapi.metadata)"
+
+// API is a protobuf API file loaded, along with the files it imports, into
+// descriptors able to handle its messages without generated code.
+type API struct {
+	Proto *protobuf.Proto
+	Files *protoregistry.Files
+}
+
+// Method is an RPC of an API.
+type Method struct {
+	Descriptor protoreflect.MethodDescriptor
+
+	// HTTP is the HTTP binding of the method, if any.
+	HTTP *protobuf.HTTPRule
+}
+
+// LoadAPI loads a protobuf API file. Imported files are searched inside
+// the directories containing it.
+func LoadAPI(filename string) (*API, error) {
+	registry := protobuf.NewRegistry(protobuf.ImportPaths(filename)...)
+	p, err := registry.Load(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Services) == 0 {
+		return nil, fmt.Errorf("%s: file has no services", filename)
+	}
+
+	files, err := registry.Descriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	return &API{
+		Proto: p,
+		Files: files,
+	}, nil
+}
+
+// IsHTTPSpec checks if the API file belongs to an http-spec service, which
+// is served through HTTP instead of gRPC.
+func (a *API) IsHTTPSpec() bool {
+	_, ok := a.Proto.Options.Get(openapiMetadataOption)
+	return ok
+}
+
+// Methods returns the methods of every service declared inside the API
+// file.
+func (a *API) Methods() ([]*Method, error) {
+	var methods []*Method
+	for _, s := range a.Proto.Services {
+		for _, m := range s.Methods {
+			method, err := a.method(s, m)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, method)
+		}
+	}
+
+	return methods, nil
+}
+
+// Method returns a method by its name, like "Service/Method". Services
+// can be given by their names or fully qualified names, and a dot can
+// also separate them from methods.
+func (a *API) Method(name string) (*Method, error) {
+	i := strings.LastIndexAny(name, "/.")
+	if i == -1 {
+		return nil, fmt.Errorf("invalid method '%s', expected 'Service/Method'", name)
+	}
+	serviceName, methodName := strings.TrimPrefix(name[:i], "/"), name[i+1:]
+
+	for _, s := range a.Proto.Services {
+		if serviceName != s.Name && serviceName != a.qualifiedName(s.Name) {
+			continue
+		}
+
+		for _, m := range s.Methods {
+			if m.Name == methodName {
+				return a.method(s, m)
+			}
+		}
+
+		return nil, fmt.Errorf("service '%s' has no method '%s'", s.Name, methodName)
+	}
+
+	return nil, fmt.Errorf("service '%s' not found in %s", serviceName, a.Proto.Filename)
+}
+
+func (a *API) method(s *protobuf.Service, m *protobuf.Method) (*Method, error) {
+	d, err := a.Files.FindDescriptorByName(protoreflect.FullName(a.qualifiedName(s.Name)))
+	if err != nil {
+		return nil, err
+	}
+
+	md := d.(protoreflect.ServiceDescriptor).Methods().ByName(protoreflect.Name(m.Name))
+	if md == nil {
+		return nil, fmt.Errorf("method '%s.%s' not found", s.Name, m.Name)
+	}
+
+	return &Method{
+		Descriptor: md,
+		HTTP:       m.HTTP,
+	}, nil
+}
+
+func (a *API) qualifiedName(name string) string {
+	if a.Proto.Package == "" {
+		return name
+	}
+
+	return a.Proto.Package + "." + name
+}
+
+// Path returns the gRPC path of the method, like "/package.Service/Method".
+func (m *Method) Path() string {
+	return fmt.Sprintf("/%s/%s", m.Descriptor.Parent().FullName(), m.Descriptor.Name())
+}
+
+// IsStreaming checks if the request or the response of the method is a
+// stream.
+func (m *Method) IsStreaming() bool {
+	return m.Descriptor.IsStreamingClient() || m.Descriptor.IsStreamingServer()
+}
@@ -0,0 +1,429 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Transport is how methods are invoked.
+type Transport string
+
+// Supported transports.
+const (
+	// TransportAuto uses gRPC, except for methods of http-spec services
+	// having an HTTP binding, since gRPC services may also declare them.
+	TransportAuto Transport = "auto"
+	TransportGRPC Transport = "grpc"
+	TransportHTTP Transport = "http"
+)
+
+// Options holds options for invoking a method of a protobuf API.
+type Options struct {
+	// Proto is the protobuf API file declaring the method.
+	Proto string
+
+	// Method is the method to invoke, like "Service/Method".
+	Method string
+
+	// Address is the server address, like "localhost:8080". HTTP addresses
+	// can also be URLs.
+	Address string
+
+	// Data is the request, as JSON. Methods receiving streams accept a
+	// sequence, or an array, of requests. When it starts with '@', requests
+	// are read from the file named by the rest of it, or from the standard
+	// input when it is "@-".
+	Data string
+
+	// Headers are sent along with requests, as gRPC metadata or HTTP
+	// headers, using the "name: value" format.
+	Headers []string
+
+	Timeout   time.Duration
+	TLS       bool
+	Transport Transport
+}
+
+// Call invokes a method of a protobuf API, without server reflection,
+// building its requests from JSON. Responses are handled as they arrive.
+func Call(ctx context.Context, options *Options, handle func(response proto.Message) error) error {
+	api, err := LoadAPI(options.Proto)
+	if err != nil {
+		return err
+	}
+
+	method, err := api.Method(options.Method)
+	if err != nil {
+		return err
+	}
+
+	data, err := readData(options.Data)
+	if err != nil {
+		return err
+	}
+
+	requests, err := decodeRequests(method.Descriptor, data)
+	if err != nil {
+		return err
+	}
+
+	headers, err := parseHeaders(options.Headers)
+	if err != nil {
+		return err
+	}
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	transport := options.Transport
+	if transport == "" || transport == TransportAuto {
+		transport = TransportGRPC
+		if api.IsHTTPSpec() && method.HTTP != nil {
+			transport = TransportHTTP
+		}
+	}
+
+	switch transport {
+	case TransportGRPC:
+		return callGRPC(ctx, options, method, requests, headers, handle)
+	case TransportHTTP:
+		return callHTTP(ctx, options, method, requests, headers, handle)
+	}
+
+	return fmt.Errorf("unsupported transport '%s'", options.Transport)
+}
+
+func readData(data string) ([]byte, error) {
+	switch {
+	case data == "@-":
+		return io.ReadAll(os.Stdin)
+	case strings.HasPrefix(data, "@"):
+		return os.ReadFile(data[1:])
+	}
+
+	return []byte(data), nil
+}
+
+// decodeRequests decodes the requests of a method from JSON. Methods
+// receiving a single request get an empty one when there is no data.
+func decodeRequests(md protoreflect.MethodDescriptor, data []byte) ([]proto.Message, error) {
+	var (
+		values  []json.RawMessage
+		decoder = json.NewDecoder(bytes.NewReader(data))
+	)
+	for {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid request data: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if md.IsStreamingClient() {
+		if len(values) == 1 && bytes.HasPrefix(bytes.TrimSpace(values[0]), []byte("[")) {
+			var items []json.RawMessage
+			if err := json.Unmarshal(values[0], &items); err != nil {
+				return nil, fmt.Errorf("invalid request data: %w", err)
+			}
+			values = items
+		}
+	} else {
+		if len(values) == 0 {
+			values = append(values, json.RawMessage("{}"))
+		}
+		if len(values) > 1 {
+			return nil, fmt.Errorf("method '%s' receives a single request, got %d", md.Name(), len(values))
+		}
+	}
+
+	requests := make([]proto.Message, len(values))
+	for i, value := range values {
+		msg := dynamicpb.NewMessage(md.Input())
+		if err := protojson.Unmarshal(value, msg); err != nil {
+			if len(values) > 1 {
+				return nil, fmt.Errorf("invalid request %d: %w", i+1, err)
+			}
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+		requests[i] = msg
+	}
+
+	return requests, nil
+}
+
+func parseHeaders(headers []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header '%s', expected 'name: value'", header)
+		}
+
+		values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return values, nil
+}
+
+func callGRPC(ctx context.Context, options *Options, method *Method, requests []proto.Message, headers map[string]string, handle func(proto.Message) error) error {
+	creds := insecure.NewCredentials()
+	if options.TLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+
+	address := options.Address
+	if _, rest, ok := strings.Cut(address, "://"); ok {
+		address = rest
+	}
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	md := metadata.New(headers)
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	d := method.Descriptor
+	if !method.IsStreaming() {
+		response := dynamicpb.NewMessage(d.Output())
+		if err := conn.Invoke(ctx, method.Path(), requests[0], response); err != nil {
+			return err
+		}
+
+		return handle(response)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    string(d.Name()),
+		ClientStreams: d.IsStreamingClient(),
+		ServerStreams: d.IsStreamingServer(),
+	}, method.Path())
+	if err != nil {
+		return err
+	}
+
+	// Requests are sent while responses are received, since bidirectional
+	// streams may answer them one by one.
+	sent := make(chan error, 1)
+	go func() {
+		for _, request := range requests {
+			if err := stream.SendMsg(request); err != nil {
+				// The error is returned by RecvMsg.
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				sent <- err
+				return
+			}
+		}
+		sent <- stream.CloseSend()
+	}()
+
+	for {
+		response := dynamicpb.NewMessage(d.Output())
+		if err := stream.RecvMsg(response); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		if err := handle(response); err != nil {
+			return err
+		}
+	}
+
+	return <-sent
+}
+
+func callHTTP(ctx context.Context, options *Options, method *Method, requests []proto.Message, headers map[string]string, handle func(proto.Message) error) error {
+	if method.HTTP == nil {
+		return fmt.Errorf("method '%s' has no HTTP binding", method.Descriptor.Name())
+	}
+	if method.IsStreaming() {
+		return fmt.Errorf("streaming method '%s' can't be invoked through HTTP", method.Descriptor.Name())
+	}
+
+	req, err := newHTTPRequest(ctx, baseURL(options), method, requests[0])
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return httpError(res.Status, body)
+	}
+
+	response := dynamicpb.NewMessage(method.Descriptor.Output())
+	if err := decodeHTTPResponse(method, body, response); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+
+	return handle(response)
+}
+
+// httpError returns the error of an HTTP response, using the message of
+// JSON errors, like the ones of gRPC gateways, when found.
+func httpError(status string, body []byte) error {
+	var e struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &e); err == nil && e.Message != "" {
+		return fmt.Errorf("%s: %s", status, e.Message)
+	}
+	if text := strings.TrimSpace(string(body)); text != "" {
+		return fmt.Errorf("%s: %s", status, text)
+	}
+
+	return errors.New(status)
+}
+
+func baseURL(options *Options) string {
+	if strings.Contains(options.Address, "://") {
+		return strings.TrimSuffix(options.Address, "/")
+	}
+
+	scheme := "http"
+	if options.TLS {
+		scheme = "https"
+	}
+
+	return scheme + "://" + options.Address
+}
+
+// newHTTPRequest builds the HTTP request of a method following its binding:
+// fields bound to path variables are set in the path, the body field (or
+// every remaining field, with "*") is sent as JSON and remaining fields are
+// sent as query parameters.
+func newHTTPRequest(ctx context.Context, base string, method *Method, request proto.Message) (*http.Request, error) {
+	rule := method.HTTP
+	template, err := parsePathTemplate(rule.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := messageFields(request)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, field := range template.Variables() {
+		value, ok := takeField(fields, field)
+		if !ok {
+			continue
+		}
+
+		s, ok := scalarString(value)
+		if !ok {
+			return nil, fmt.Errorf("path variable '%s' is not bound to a scalar field", field)
+		}
+		values[field] = s
+	}
+
+	path, err := template.Expand(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	switch rule.Body {
+	case "":
+	case "*":
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		fields = nil
+
+	default:
+		value, ok := takeField(fields, rule.Body)
+		if !ok {
+			value = map[string]any{}
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	if query := queryValues(fields).Encode(); query != "" {
+		path += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rule.Method, base+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
+// decodeHTTPResponse decodes the JSON body of an HTTP response. When the
+// method binding has a response body field, the body is its value.
+func decodeHTTPResponse(method *Method, body []byte, response proto.Message) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if field := method.HTTP.ResponseBody; field != "" && field != "*" {
+		data, err := json.Marshal(map[string]json.RawMessage{field: body})
+		if err != nil {
+			return err
+		}
+		body = data
+	}
+
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, response)
+}
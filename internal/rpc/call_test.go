@@ -0,0 +1,407 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var (
+	billingAPI  = filepath.Join("testdata", "acme", "billing", "billing_api.proto")
+	paymentsAPI = filepath.Join("testdata", "acme", "payments", "payments_api.proto")
+)
+
+// startGRPCServer starts a gRPC server for the billing API that, without
+// generated code, echoes requests back.
+func startGRPCServer(t *testing.T) string {
+	t.Helper()
+
+	api, err := LoadAPI(billingAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		name, _ := grpc.MethodFromServerStream(stream)
+		method, err := api.Method(name)
+		if err != nil {
+			return err
+		}
+
+		var (
+			md       = method.Descriptor
+			requests []*dynamicpb.Message
+		)
+		for {
+			request := dynamicpb.NewMessage(md.Input())
+			if err := stream.RecvMsg(request); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return err
+			}
+			requests = append(requests, request)
+
+			// Bidirectional streams answer every request right away.
+			if md.IsStreamingServer() && md.IsStreamingClient() {
+				if err := stream.SendMsg(request); err != nil {
+					return err
+				}
+			}
+			if !md.IsStreamingClient() {
+				break
+			}
+		}
+
+		switch md.Name() {
+		case "GetInvoice":
+			response := dynamicpb.NewMessage(md.Output())
+			response.Set(md.Output().Fields().ByName("id"), requests[0].Get(md.Input().Fields().ByName("id")))
+			if values := metadata.ValueFromIncomingContext(stream.Context(), "x-tenant"); len(values) > 0 {
+				tags := response.Mutable(md.Output().Fields().ByName("tags")).List()
+				tags.Append(protoreflect.ValueOfString(values[0]))
+			}
+			return stream.SendMsg(response)
+
+		case "WatchInvoices":
+			limit := requests[0].Get(md.Input().Fields().ByName("limit")).Int()
+			for i := range limit {
+				response := dynamicpb.NewMessage(md.Output())
+				response.Set(md.Output().Fields().ByName("id"), protoreflect.ValueOfString(fmt.Sprintf("inv-%d", i+1)))
+				if err := stream.SendMsg(response); err != nil {
+					return err
+				}
+			}
+
+		case "UploadInvoices":
+			response := dynamicpb.NewMessage(md.Output())
+			response.Set(md.Output().Fields().ByName("count"), protoreflect.ValueOfInt32(int32(len(requests))))
+			return stream.SendMsg(response)
+		}
+
+		return nil
+	}))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func call(t *testing.T, options *Options) ([]string, error) {
+	t.Helper()
+
+	var responses []string
+	err := Call(context.Background(), options, func(response proto.Message) error {
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(response)
+		if err != nil {
+			return err
+		}
+
+		// Compacts the output, since protojson adds random spaces.
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		data, _ = json.Marshal(v)
+
+		responses = append(responses, string(data))
+		return nil
+	})
+
+	return responses, err
+}
+
+func TestCallGRPC(t *testing.T) {
+	address := startGRPCServer(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		data    string
+		headers []string
+		want    []string
+	}{
+		{
+			name:    "unary",
+			method:  "BillingService/GetInvoice",
+			data:    `{"customer_id": "c1", "id": "inv-1"}`,
+			headers: []string{"X-Tenant: acme"},
+			want:    []string{`{"id":"inv-1","tags":["acme"]}`},
+		},
+		{
+			name:   "server streaming",
+			method: "acme.billing.BillingService/WatchInvoices",
+			data:   `{"limit": 2}`,
+			want:   []string{`{"id":"inv-1"}`, `{"id":"inv-2"}`},
+		},
+		{
+			name:   "client streaming",
+			method: "BillingService.UploadInvoices",
+			data:   `[{"id": "inv-1"}, {"id": "inv-2"}, {"id": "inv-3"}]`,
+			want:   []string{`{"count":3}`},
+		},
+		{
+			name:   "bidirectional streaming",
+			method: "BillingService/SyncInvoices",
+			data:   `{"id": "inv-1", "status": "STATUS_PAID"} {"id": "inv-2", "createdAt": "2025-01-02T03:04:05Z"}`,
+			want:   []string{`{"id":"inv-1","status":"STATUS_PAID"}`, `{"created_at":"2025-01-02T03:04:05Z","id":"inv-2"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// gRPC services are invoked through gRPC by default, even when
+			// their methods have HTTP bindings.
+			responses, err := call(t, &Options{
+				Proto:   billingAPI,
+				Method:  tt.method,
+				Address: address,
+				Data:    tt.data,
+				Headers: tt.headers,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(responses, tt.want) {
+				t.Errorf("got responses %v, want %v", responses, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/customers/c 1/invoices/inv-1":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if got, want := r.URL.RawQuery, "fields=id&fields=status&include_items=true"; got != want {
+				http.Error(w, "unexpected query: "+got, http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "inv-1", "customerId": "c 1", "unknown": true}`))
+
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/customers/c1/invoices":
+			body, _ := io.ReadAll(r.Body)
+			if got, want := strings.TrimSpace(string(body)), `{"id":"inv-2","total":{"currency":"EUR","units":"10"}}`; got != want {
+				http.Error(w, "unexpected body: "+got, http.StatusBadRequest)
+				return
+			}
+			if got, want := r.URL.RawQuery, "request_id=r1"; got != want {
+				http.Error(w, "unexpected query: "+got, http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write(body)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/payments/p1":
+			_, _ = w.Write([]byte(`{"id": "p1", "amount": "5"}`))
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	t.Run("query parameters", func(t *testing.T) {
+		responses, err := call(t, &Options{
+			Proto:     billingAPI,
+			Method:    "BillingService/GetInvoice",
+			Address:   address,
+			Data:      `{"customer_id": "c 1", "id": "inv-1", "fields": ["id", "status"], "include_items": true}`,
+			Headers:   []string{"Authorization: Bearer token"},
+			Transport: TransportHTTP,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{`{"customer_id":"c 1","id":"inv-1"}`}; !reflect.DeepEqual(responses, want) {
+			t.Errorf("got responses %v, want %v", responses, want)
+		}
+	})
+
+	t.Run("body and response body fields", func(t *testing.T) {
+		responses, err := call(t, &Options{
+			Proto:     billingAPI,
+			Method:    "BillingService/CreateInvoice",
+			Address:   server.URL,
+			Data:      `{"customer_id": "c1", "request_id": "r1", "invoice": {"id": "inv-2", "total": {"currency": "EUR", "units": 10}}}`,
+			Transport: TransportHTTP,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{`{"invoice":{"id":"inv-2","total":{"currency":"EUR","units":"10"}}}`}; !reflect.DeepEqual(responses, want) {
+			t.Errorf("got responses %v, want %v", responses, want)
+		}
+	})
+
+	t.Run("http-spec service", func(t *testing.T) {
+		responses, err := call(t, &Options{
+			Proto:   paymentsAPI,
+			Method:  "PaymentsService/GetPayment",
+			Address: address,
+			Data:    `{"id": "p1"}`,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{`{"amount":"5","id":"p1"}`}; !reflect.DeepEqual(responses, want) {
+			t.Errorf("got responses %v, want %v", responses, want)
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		_, err := call(t, &Options{
+			Proto:     billingAPI,
+			Method:    "BillingService/GetInvoice",
+			Address:   address,
+			Data:      `{"customer_id": "c 1", "id": "inv-1"}`,
+			Transport: TransportHTTP,
+		})
+		if err == nil || !strings.Contains(err.Error(), "401 Unauthorized: unauthorized") {
+			t.Errorf("got error %v", err)
+		}
+	})
+
+	t.Run("missing path variable", func(t *testing.T) {
+		_, err := call(t, &Options{
+			Proto:     billingAPI,
+			Method:    "BillingService/GetInvoice",
+			Address:   address,
+			Data:      `{"id": "inv-1"}`,
+			Transport: TransportHTTP,
+		})
+		if err == nil || !strings.Contains(err.Error(), "missing value of path variable 'customer_id'") {
+			t.Errorf("got error %v", err)
+		}
+	})
+
+	t.Run("streaming method", func(t *testing.T) {
+		_, err := call(t, &Options{
+			Proto:     billingAPI,
+			Method:    "BillingService/WatchInvoices",
+			Address:   address,
+			Transport: TransportHTTP,
+		})
+		if err == nil || !strings.Contains(err.Error(), "has no HTTP binding") {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestCallErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		data   string
+		want   string
+	}{
+		{
+			name:   "unknown service",
+			method: "OrdersService/GetOrder",
+			want:   "service 'OrdersService' not found",
+		},
+		{
+			name:   "unknown method",
+			method: "BillingService/GetOrder",
+			want:   "service 'BillingService' has no method 'GetOrder'",
+		},
+		{
+			name:   "invalid method",
+			method: "GetInvoice",
+			want:   "invalid method 'GetInvoice'",
+		},
+		{
+			name:   "several requests",
+			method: "BillingService/GetInvoice",
+			data:   `{"id": "1"} {"id": "2"}`,
+			want:   "method 'GetInvoice' receives a single request, got 2",
+		},
+		{
+			name:   "unknown field",
+			method: "BillingService/UploadInvoices",
+			data:   `{"id": "1"} {"number": "2"}`,
+			want:   "invalid request 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := call(t, &Options{
+				Proto:   billingAPI,
+				Method:  tt.method,
+				Address: "127.0.0.1:1",
+				Data:    tt.data,
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	tests := []struct {
+		path   string
+		values map[string]string
+		want   string
+	}{
+		{
+			path:   "/v1/invoices",
+			values: map[string]string{},
+			want:   "/v1/invoices",
+		},
+		{
+			path:   "/v1/{name=shelves/*}/books/{book.id}:publish",
+			values: map[string]string{"name": "shelves/a b", "book.id": "1/2"},
+			want:   "/v1/shelves/a%20b/books/1%2F2:publish",
+		},
+		{
+			path:   "/v1/files/{path=**}",
+			values: map[string]string{"path": "a/b/c.txt"},
+			want:   "/v1/files/a/b/c.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		template, err := parsePathTemplate(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := template.Expand(tt.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
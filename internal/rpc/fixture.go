@@ -0,0 +1,218 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Fixture is a predefined answer of a method, declared inside a JSON file
+// of a fixtures directory. Files can hold a single fixture or an array of
+// them.
+type Fixture struct {
+	// Method is the method answered, like "Service/Method".
+	Method string `json:"method"`
+
+	// Request holds the request fields that must match for the fixture to
+	// be used. Fixtures without them match every request.
+	Request json.RawMessage `json:"request,omitempty"`
+
+	// Response is the response sent. Methods returning streams send
+	// Responses instead.
+	Response  json.RawMessage   `json:"response,omitempty"`
+	Responses []json.RawMessage `json:"responses,omitempty"`
+
+	// Error, when set, is returned instead of responses.
+	Error *FixtureError `json:"error,omitempty"`
+
+	// Filename is the file where the fixture was declared.
+	Filename string `json:"-"`
+
+	method    *Method
+	fields    map[string]any
+	responses []proto.Message
+}
+
+// FixtureError is an error returned by a fixture.
+type FixtureError struct {
+	// Code is the gRPC status code, by name (like "NOT_FOUND") or number.
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+// LoadFixtures loads the fixtures of the JSON files found inside dir, in
+// lexical order, checking that their methods exist in the API and that
+// their requests and responses are valid.
+func LoadFixtures(api *API, dir string) ([]*Fixture, error) {
+	var fixtures []*Fixture
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		loaded, err := loadFixtureFile(api, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fixtures = append(fixtures, loaded...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fixtures, nil
+}
+
+func loadFixtureFile(api *API, filename string) ([]*Fixture, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*Fixture
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &fixtures)
+	} else {
+		fixture := &Fixture{}
+		err = json.Unmarshal(data, fixture)
+		fixtures = append(fixtures, fixture)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, fixture := range fixtures {
+		if err := fixture.load(api, filename); err != nil {
+			if len(fixtures) > 1 {
+				return nil, fmt.Errorf("fixture %d: %w", i+1, err)
+			}
+			return nil, err
+		}
+	}
+
+	return fixtures, nil
+}
+
+func (f *Fixture) load(api *API, filename string) error {
+	method, err := api.Method(f.Method)
+	if err != nil {
+		return err
+	}
+
+	f.Filename = filename
+	f.method = method
+
+	// Requests are decoded as messages so fields can be given by their
+	// protobuf or JSON names and values are compared in a single format.
+	request := dynamicpb.NewMessage(method.Descriptor.Input())
+	if len(f.Request) > 0 {
+		if err := protojson.Unmarshal(f.Request, request); err != nil {
+			return fmt.Errorf("invalid request: %w", err)
+		}
+	}
+	if f.fields, err = messageFields(request); err != nil {
+		return err
+	}
+
+	responses := f.Responses
+	if len(f.Response) > 0 {
+		responses = append([]json.RawMessage{f.Response}, responses...)
+	}
+	if len(responses) > 1 && !method.Descriptor.IsStreamingServer() {
+		return fmt.Errorf("method '%s' returns a single response, got %d", method.Descriptor.Name(), len(responses))
+	}
+
+	for _, data := range responses {
+		response := dynamicpb.NewMessage(method.Descriptor.Output())
+		if err := protojson.Unmarshal(data, response); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		f.responses = append(f.responses, response)
+	}
+
+	// Fixtures without responses answer empty messages.
+	if len(f.responses) == 0 && !method.Descriptor.IsStreamingServer() {
+		f.responses = append(f.responses, dynamicpb.NewMessage(method.Descriptor.Output()))
+	}
+
+	return nil
+}
+
+func (f *Fixture) err() error {
+	if f.Error == nil {
+		return nil
+	}
+
+	return status.Error(f.Error.Code, f.Error.Message)
+}
+
+// findFixture returns the fixture of a method matching a request. When
+// several fixtures match, the one matching more fields wins, or the first
+// one declared, on ties.
+func findFixture(fixtures []*Fixture, method *Method, request proto.Message) (*Fixture, error) {
+	fields, err := messageFields(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		found *Fixture
+		best  = -1
+	)
+	for _, f := range fixtures {
+		if f.method.Descriptor.FullName() != method.Descriptor.FullName() {
+			continue
+		}
+
+		if score, ok := matchFields(f.fields, fields); ok && score > best {
+			found, best = f, score
+		}
+	}
+
+	return found, nil
+}
+
+// matchFields checks if every field of want is found in got, with the same
+// value, returning how many (non-message) fields were compared.
+func matchFields(want, got map[string]any) (int, bool) {
+	score := 0
+	for name, value := range want {
+		actual, ok := got[name]
+		if !ok {
+			return 0, false
+		}
+
+		nested, isMessage := value.(map[string]any)
+		actualNested, actualIsMessage := actual.(map[string]any)
+		if isMessage && actualIsMessage {
+			n, ok := matchFields(nested, actualNested)
+			if !ok {
+				return 0, false
+			}
+			score += n
+			continue
+		}
+
+		if !reflect.DeepEqual(value, actual) {
+			return 0, false
+		}
+		score++
+	}
+
+	return score, true
+}
@@ -0,0 +1,291 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// pathTemplate is a parsed HTTP path template of the google.api.http
+// option, like "/v1/{name=shelves/*}/books/{id}:publish".
+type pathTemplate struct {
+	segments []*templateSegment
+	verb     string
+	pattern  *regexp.Regexp
+}
+
+// templateSegment is a literal segment of a path template or a variable,
+// which can match several segments.
+type templateSegment struct {
+	Literal string
+
+	// Field is the path of the request field bound to a variable, like
+	// "book.id".
+	Field string
+
+	// Pattern holds the segments matched by a variable, which default to a
+	// single one ("*").
+	Pattern []string
+}
+
+func parsePathTemplate(path string) (*pathTemplate, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid HTTP path '%s': it must start with '/'", path)
+	}
+
+	t := &pathTemplate{}
+	rest := path[1:]
+	if i := strings.LastIndex(rest, ":"); i != -1 && !strings.ContainsAny(rest[i:], "/}") {
+		rest, t.verb = rest[:i], rest[i+1:]
+	}
+
+	for rest != "" {
+		if !strings.HasPrefix(rest, "{") {
+			literal, next, _ := strings.Cut(rest, "/")
+			t.segments = append(t.segments, &templateSegment{Literal: literal})
+			rest = next
+			continue
+		}
+
+		end := strings.Index(rest, "}")
+		if end == -1 {
+			return nil, fmt.Errorf("invalid HTTP path '%s': unterminated variable", path)
+		}
+
+		field, pattern, found := strings.Cut(rest[1:end], "=")
+		if field == "" {
+			return nil, fmt.Errorf("invalid HTTP path '%s': variable without field", path)
+		}
+		if !found {
+			pattern = "*"
+		}
+
+		t.segments = append(t.segments, &templateSegment{
+			Field:   field,
+			Pattern: strings.Split(pattern, "/"),
+		})
+
+		rest = strings.TrimPrefix(rest[end+1:], "/")
+	}
+
+	pattern, err := regexp.Compile(t.expression())
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP path '%s': %w", path, err)
+	}
+	t.pattern = pattern
+
+	return t, nil
+}
+
+// expression returns the regular expression matching paths of the
+// template, with a group for each variable.
+func (t *pathTemplate) expression() string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, s := range t.segments {
+		b.WriteString("/")
+		if s.Field == "" {
+			b.WriteString(regexp.QuoteMeta(s.Literal))
+			continue
+		}
+
+		parts := make([]string, len(s.Pattern))
+		for i, p := range s.Pattern {
+			switch p {
+			case "*":
+				parts[i] = "[^/]+"
+			case "**":
+				parts[i] = ".+"
+			default:
+				parts[i] = regexp.QuoteMeta(p)
+			}
+		}
+		b.WriteString("(" + strings.Join(parts, "/") + ")")
+	}
+	if len(t.segments) == 0 {
+		b.WriteString("/")
+	}
+	if t.verb != "" {
+		b.WriteString(regexp.QuoteMeta(":" + t.verb))
+	}
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// Variables returns the fields bound to the template variables.
+func (t *pathTemplate) Variables() []string {
+	var fields []string
+	for _, s := range t.segments {
+		if s.Field != "" {
+			fields = append(fields, s.Field)
+		}
+	}
+
+	return fields
+}
+
+// Expand returns the path with its variables replaced by values.
+func (t *pathTemplate) Expand(values map[string]string) (string, error) {
+	var b strings.Builder
+	for _, s := range t.segments {
+		b.WriteString("/")
+		if s.Field == "" {
+			b.WriteString(s.Literal)
+			continue
+		}
+
+		value, ok := values[s.Field]
+		if !ok || value == "" {
+			return "", fmt.Errorf("missing value of path variable '%s'", s.Field)
+		}
+
+		// Variables matching several segments keep their slashes.
+		parts := []string{value}
+		if len(s.Pattern) > 1 || s.Pattern[0] == "**" {
+			parts = strings.Split(value, "/")
+		}
+		for i, p := range parts {
+			parts[i] = url.PathEscape(p)
+		}
+		b.WriteString(strings.Join(parts, "/"))
+	}
+	if b.Len() == 0 {
+		b.WriteString("/")
+	}
+	if t.verb != "" {
+		b.WriteString(":" + t.verb)
+	}
+
+	return b.String(), nil
+}
+
+// Match checks if a path, with its segments still escaped, matches the
+// template, returning the values of its variables.
+func (t *pathTemplate) Match(path string) (map[string]string, bool) {
+	matches := t.pattern.FindStringSubmatch(path)
+	if matches == nil {
+		return nil, false
+	}
+
+	var (
+		values = make(map[string]string)
+		i      = 1
+	)
+	for _, s := range t.segments {
+		if s.Field == "" {
+			continue
+		}
+
+		value, err := url.PathUnescape(matches[i])
+		if err != nil {
+			return nil, false
+		}
+		values[s.Field] = value
+		i++
+	}
+
+	return values, true
+}
+
+// messageFields returns the fields of a message as JSON values, keyed by
+// their protobuf names.
+func messageFields(msg proto.Message) (map[string]any, error) {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	fields := make(map[string]any)
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// takeField removes a field, given by its path (like "book.id"), from JSON
+// values, returning its value.
+func takeField(fields map[string]any, path string) (any, bool) {
+	name, rest, nested := strings.Cut(path, ".")
+	value, ok := fields[name]
+	if !ok {
+		return nil, false
+	}
+	if !nested {
+		delete(fields, name)
+		return value, true
+	}
+
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	return takeField(m, rest)
+}
+
+// scalarString returns the text of a scalar JSON value.
+func scalarString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+
+	return "", false
+}
+
+// queryValues flattens JSON values into query parameters: fields of nested
+// messages are named with their paths, like "book.id", and repeated fields
+// are repeated parameters.
+func queryValues(fields map[string]any) url.Values {
+	query := make(url.Values)
+
+	var add func(prefix string, value any)
+	add = func(prefix string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				name := key
+				if prefix != "" {
+					name = prefix + "." + key
+				}
+				add(name, v[key])
+			}
+
+		case []any:
+			for _, item := range v {
+				add(prefix, item)
+			}
+
+		default:
+			if s, ok := scalarString(v); ok {
+				query.Add(prefix, s)
+			}
+		}
+	}
+	add("", fields)
+
+	return query
+}
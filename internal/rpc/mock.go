@@ -0,0 +1,517 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+// MockOptions holds options for mocking a protobuf API.
+type MockOptions struct {
+	// Proto is the protobuf API file.
+	Proto string
+
+	// Fixtures is a directory with fixtures answering methods. Requests
+	// without fixtures are answered with sample data.
+	Fixtures string
+
+	// Handle, when set, is called after every request is answered. It may
+	// be called concurrently.
+	Handle func(call *MockCall)
+}
+
+// MockServer answers the methods of a protobuf API, through gRPC and,
+// for methods with HTTP bindings, through HTTP.
+type MockServer struct {
+	methods  map[string]*Method
+	routes   []*route
+	fixtures []*Fixture
+	handle   func(call *MockCall)
+}
+
+// MockCall describes how a request was answered.
+type MockCall struct {
+	Transport Transport `json:"transport"`
+	Method    string    `json:"method"`
+
+	// Fixture is the file of the fixture used, if any. Otherwise, the
+	// request was answered with sample data.
+	Fixture string `json:"fixture,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// route is an HTTP binding of a method.
+type route struct {
+	method   *Method
+	verb     string
+	template *pathTemplate
+	body     string
+	response string
+}
+
+// NewMockServer creates a server mocking a protobuf API.
+func NewMockServer(options *MockOptions) (*MockServer, error) {
+	api, err := LoadAPI(options.Proto)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := api.Methods()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &MockServer{
+		methods: make(map[string]*Method),
+		handle:  options.Handle,
+	}
+	for _, method := range methods {
+		s.methods[method.Path()] = method
+		if err := s.addRoutes(method); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Fixtures != "" {
+		if s.fixtures, err = LoadFixtures(api, options.Fixtures); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// addRoutes adds the HTTP bindings of a method. Streaming methods are only
+// served through gRPC.
+func (s *MockServer) addRoutes(method *Method) error {
+	if method.HTTP == nil || method.IsStreaming() {
+		return nil
+	}
+
+	rules := append([]*protobuf.HTTPRule{method.HTTP}, method.HTTP.AdditionalBindings...)
+	for _, rule := range rules {
+		template, err := parsePathTemplate(rule.Path)
+		if err != nil {
+			return fmt.Errorf("method '%s': %w", method.Descriptor.FullName(), err)
+		}
+
+		s.routes = append(s.routes, &route{
+			method:   method,
+			verb:     rule.Method,
+			template: template,
+			body:     rule.Body,
+			response: rule.ResponseBody,
+		})
+	}
+
+	return nil
+}
+
+// Methods returns the methods of the mocked API.
+func (s *MockServer) Methods() []*Method {
+	methods := make([]*Method, 0, len(s.methods))
+	for _, method := range s.methods {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Path() < methods[j].Path()
+	})
+
+	return methods
+}
+
+// Fixtures returns the loaded fixtures.
+func (s *MockServer) Fixtures() []*Fixture {
+	return s.fixtures
+}
+
+// HasHTTP checks if the API has methods that can be served through HTTP.
+func (s *MockServer) HasHTTP() bool {
+	return len(s.routes) > 0
+}
+
+// Serve serves the API through gRPC, using grpcListener, and through HTTP,
+// using httpListener, when it is not nil, until ctx is done.
+func (s *MockServer) Serve(ctx context.Context, grpcListener, httpListener net.Listener) error {
+	var (
+		grpcServer = grpc.NewServer(grpc.UnknownServiceHandler(s.serveGRPC))
+		httpServer = &http.Server{Handler: s}
+		errs       = make(chan error, 2)
+		wg         sync.WaitGroup
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			errs <- err
+		}
+	}()
+
+	if httpListener != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := httpServer.Serve(httpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+
+	grpcServer.Stop()
+	_ = httpServer.Close()
+	wg.Wait()
+
+	return err
+}
+
+func (s *MockServer) serveGRPC(_ any, stream grpc.ServerStream) error {
+	name, _ := grpc.MethodFromServerStream(stream)
+	method, ok := s.methods[name]
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", name)
+	}
+
+	var (
+		d       = method.Descriptor
+		request proto.Message
+	)
+	for {
+		msg := dynamicpb.NewMessage(d.Input())
+		if err := stream.RecvMsg(msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		request = msg
+
+		// Bidirectional streams answer every request, while methods
+		// receiving a stream answer only its last request.
+		if d.IsStreamingClient() && d.IsStreamingServer() {
+			if err := s.answerGRPC(stream, method, request); err != nil {
+				return err
+			}
+		}
+		if !d.IsStreamingClient() {
+			break
+		}
+	}
+
+	if d.IsStreamingClient() && d.IsStreamingServer() {
+		return nil
+	}
+	if request == nil {
+		request = dynamicpb.NewMessage(d.Input())
+	}
+
+	return s.answerGRPC(stream, method, request)
+}
+
+func (s *MockServer) answerGRPC(stream grpc.ServerStream, method *Method, request proto.Message) error {
+	responses, fixture, err := s.answer(method, request)
+	if err == nil {
+		for _, response := range responses {
+			if err = stream.SendMsg(response); err != nil {
+				break
+			}
+		}
+	}
+
+	s.notify(TransportGRPC, method, fixture, err)
+	return err
+}
+
+// answer returns the responses of a request, from the fixture matching it
+// or with sample data.
+func (s *MockServer) answer(method *Method, request proto.Message) ([]proto.Message, *Fixture, error) {
+	fixture, err := findFixture(s.fixtures, method, request)
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+	if fixture == nil {
+		return []proto.Message{sampleResponse(method.Descriptor, request)}, nil, nil
+	}
+	if err := fixture.err(); err != nil {
+		return nil, fixture, err
+	}
+
+	return fixture.responses, fixture, nil
+}
+
+func (s *MockServer) notify(transport Transport, method *Method, fixture *Fixture, err error) {
+	if s.handle == nil {
+		return
+	}
+
+	call := &MockCall{
+		Transport: transport,
+		Method:    fmt.Sprintf("%s/%s", method.Descriptor.Parent().Name(), method.Descriptor.Name()),
+	}
+	if fixture != nil {
+		call.Fixture = fixture.Filename
+	}
+	if err != nil {
+		st := status.Convert(err)
+		call.Error = fmt.Sprintf("%s: %s", st.Code(), st.Message())
+	}
+
+	s.handle(call)
+}
+
+// ServeHTTP answers requests of methods with HTTP bindings.
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt, values := s.findRoute(r)
+	if rt == nil {
+		writeHTTPError(w, status.Errorf(codes.NotFound, "no method bound to %s %s", r.Method, r.URL.Path))
+		return
+	}
+
+	request, err := decodeHTTPRequest(r, rt, values)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		s.notify(TransportHTTP, rt.method, nil, err)
+		writeHTTPError(w, err)
+		return
+	}
+
+	responses, fixture, err := s.answer(rt.method, request)
+	s.notify(TransportHTTP, rt.method, fixture, err)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	body, err := encodeHTTPResponse(rt, responses[0])
+	if err != nil {
+		writeHTTPError(w, status.Error(codes.Internal, err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+func (s *MockServer) findRoute(r *http.Request) (*route, map[string]string) {
+	for _, rt := range s.routes {
+		if rt.verb != r.Method {
+			continue
+		}
+		if values, ok := rt.template.Match(r.URL.EscapedPath()); ok {
+			return rt, values
+		}
+	}
+
+	return nil, nil
+}
+
+// decodeHTTPRequest builds the request of a method from an HTTP request,
+// following its binding: path variables, the body and query parameters
+// set request fields.
+func decodeHTTPRequest(r *http.Request, rt *route, variables map[string]string) (proto.Message, error) {
+	var (
+		input  = rt.method.Descriptor.Input()
+		fields = make(map[string]any)
+	)
+
+	if rt.body != "" {
+		decoder := json.NewDecoder(r.Body)
+		decoder.UseNumber()
+
+		var body any
+		if err := decoder.Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid body: %w", err)
+		}
+
+		if rt.body == "*" && body != nil {
+			m, ok := body.(map[string]any)
+			if !ok {
+				return nil, errors.New("invalid body: it must be a JSON object")
+			}
+			fields = m
+		} else if body != nil {
+			fields[rt.body] = body
+		}
+	}
+
+	for name, values := range r.URL.Query() {
+		if err := setFieldText(fields, input, name, values); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range variables {
+		if err := setFieldText(fields, input, name, []string{value}); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	request := dynamicpb.NewMessage(input)
+	if err := protojson.Unmarshal(data, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// setFieldText sets a field, given by its path (like "book.id"), of JSON
+// values from its text, as received in paths and query parameters.
+func setFieldText(fields map[string]any, md protoreflect.MessageDescriptor, path string, values []string) error {
+	name, rest, nested := strings.Cut(path, ".")
+
+	fd := md.Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		fd = md.Fields().ByJSONName(name)
+	}
+	if fd == nil {
+		return fmt.Errorf("unknown field '%s'", path)
+	}
+	key := string(fd.Name())
+
+	if nested {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return fmt.Errorf("field '%s' is not a message", name)
+		}
+
+		m, ok := fields[key].(map[string]any)
+		if !ok {
+			m = make(map[string]any)
+			fields[key] = m
+		}
+
+		return setFieldText(m, fd.Message(), rest, values)
+	}
+
+	var items []any
+	for _, value := range values {
+		item, err := textValue(fd, value)
+		if err != nil {
+			return fmt.Errorf("invalid value of field '%s': %w", path, err)
+		}
+		items = append(items, item)
+	}
+
+	if fd.IsList() {
+		fields[key] = items
+		return nil
+	}
+	if len(items) > 0 {
+		fields[key] = items[len(items)-1]
+	}
+
+	return nil
+}
+
+// textValue converts the text of a field into its JSON value. Numbers
+// are kept as strings, which protojson accepts.
+func textValue(fd protoreflect.FieldDescriptor, value string) (any, error) {
+	switch {
+	case fd.IsMap():
+		return nil, errors.New("map fields can't be set from text")
+	case fd.Kind() == protoreflect.BoolKind:
+		return strconv.ParseBool(value)
+	case fd.Message() != nil && !strings.HasPrefix(string(fd.Message().FullName()), "google.protobuf."):
+		return nil, errors.New("message fields can't be set from text")
+	}
+
+	return value, nil
+}
+
+// encodeHTTPResponse encodes the response of a method as JSON. When the
+// binding has a response body field, only its value is encoded.
+func encodeHTTPResponse(rt *route, response proto.Message) ([]byte, error) {
+	if rt.response == "" || rt.response == "*" {
+		return protojson.Marshal(response)
+	}
+
+	fd := rt.method.Descriptor.Output().Fields().ByName(protoreflect.Name(rt.response))
+	if fd == nil {
+		return nil, fmt.Errorf("unknown response body field '%s'", rt.response)
+	}
+
+	// Fields without values are encoded with their default ones.
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields[fd.JSONName()], nil
+}
+
+// writeHTTPError writes an error as JSON, with the HTTP status
+// corresponding to its gRPC code.
+func writeHTTPError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	body, _ := json.Marshal(map[string]any{
+		"code":    int(st.Code()),
+		"message": st.Message(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(st.Code()))
+	_, _ = w.Write(body)
+}
+
+// httpStatus maps gRPC codes to HTTP statuses, like gRPC gateways do.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,352 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// startMockServer starts a mock server of the billing API, returning its
+// gRPC and HTTP addresses.
+func startMockServer(t *testing.T, fixtures string, handle func(*MockCall)) (string, string) {
+	t.Helper()
+
+	server, err := NewMockServer(&MockOptions{
+		Proto:    billingAPI,
+		Fixtures: fixtures,
+		Handle:   handle,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !server.HasHTTP() {
+		t.Fatal("expected methods with HTTP bindings")
+	}
+
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(ctx, grpcListener, httpListener)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	})
+
+	return grpcListener.Addr().String(), httpListener.Addr().String()
+}
+
+func TestMockServer(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	grpcAddress, httpAddress := startMockServer(t, filepath.Join("testdata", "fixtures"), func(call *MockCall) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		answer := "sample"
+		if call.Fixture != "" {
+			answer = filepath.Base(call.Fixture)
+		}
+		if call.Error != "" {
+			answer = call.Error
+		}
+		calls = append(calls, strings.Join([]string{string(call.Transport), call.Method, answer}, " "))
+	})
+
+	tests := []struct {
+		name      string
+		method    string
+		data      string
+		transport Transport
+		want      []string
+		wantErr   string
+		wantCall  string
+	}{
+		{
+			name:      "most specific fixture",
+			method:    "BillingService/GetInvoice",
+			data:      `{"customer_id": "c1", "id": "42"}`,
+			transport: TransportHTTP,
+			want:      []string{`{"customer_id":"c1","id":"42","status":"STATUS_PAID"}`},
+			wantCall:  "http BillingService/GetInvoice invoices.json",
+		},
+		{
+			name:      "fixture without request fields",
+			method:    "BillingService/GetInvoice",
+			data:      `{"customer_id": "c2", "id": "42"}`,
+			transport: TransportGRPC,
+			want:      []string{`{"id":"any","status":"STATUS_OPEN"}`},
+			wantCall:  "grpc BillingService/GetInvoice invoices.json",
+		},
+		{
+			name:      "fixture error through HTTP",
+			method:    "BillingService/GetInvoice",
+			data:      `{"customer_id": "c1", "id": "404"}`,
+			transport: TransportHTTP,
+			wantErr:   "404 Not Found: invoice not found",
+			wantCall:  "http BillingService/GetInvoice NotFound: invoice not found",
+		},
+		{
+			name:      "fixture error through gRPC",
+			method:    "BillingService/GetInvoice",
+			data:      `{"customer_id": "c1", "id": "404"}`,
+			transport: TransportGRPC,
+			wantErr:   "code = NotFound desc = invoice not found",
+			wantCall:  "grpc BillingService/GetInvoice NotFound: invoice not found",
+		},
+		{
+			name:      "body and response body fields",
+			method:    "BillingService/CreateInvoice",
+			data:      `{"customer_id": "c1", "invoice": {"total": {"currency": "EUR", "units": 10}}}`,
+			transport: TransportHTTP,
+			want:      []string{`{"invoice":{"id":"inv-eur","total":{"currency":"EUR","units":"10"}}}`},
+			wantCall:  "http BillingService/CreateInvoice create.json",
+		},
+		{
+			name:      "server streaming fixture",
+			method:    "BillingService/WatchInvoices",
+			data:      `{"customer_id": "c1"}`,
+			transport: TransportGRPC,
+			want:      []string{`{"id":"inv-1"}`, `{"id":"inv-2"}`},
+			wantCall:  "grpc BillingService/WatchInvoices watch.json",
+		},
+		{
+			name:      "client streaming sample",
+			method:    "BillingService/UploadInvoices",
+			data:      `{"id": "inv-1"} {"id": "inv-2"}`,
+			transport: TransportGRPC,
+			want:      []string{`{"count":1}`},
+			wantCall:  "grpc BillingService/UploadInvoices sample",
+		},
+		{
+			name:      "bidirectional streaming sample",
+			method:    "BillingService/SyncInvoices",
+			data:      `{"id": "inv-1"} {"id": "inv-2"}`,
+			transport: TransportGRPC,
+			want: []string{
+				`{"created_at":"2025-01-01T00:00:00Z","customer_id":"636ab4ad-3287-b737-36e3-3eaaf4d5b97a","id":"inv-1","status":"STATUS_OPEN","tags":["tags"],"total":{"currency":"currency","units":"1"}}`,
+				`{"created_at":"2025-01-01T00:00:00Z","customer_id":"636ab4ad-3287-b737-36e3-3eaaf4d5b97a","id":"inv-2","status":"STATUS_OPEN","tags":["tags"],"total":{"currency":"currency","units":"1"}}`,
+			},
+			wantCall: "grpc BillingService/SyncInvoices sample",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := grpcAddress
+			if tt.transport == TransportHTTP {
+				address = httpAddress
+			}
+
+			mu.Lock()
+			calls = nil
+			mu.Unlock()
+
+			responses, err := call(t, &Options{
+				Proto:     billingAPI,
+				Method:    tt.method,
+				Address:   address,
+				Data:      tt.data,
+				Transport: tt.transport,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(responses, tt.want) {
+				t.Errorf("got responses %v, want %v", responses, tt.want)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(calls) == 0 || calls[len(calls)-1] != tt.wantCall {
+				t.Errorf("got calls %v, want %q", calls, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestMockServerHTTPRequests(t *testing.T) {
+	_, httpAddress := startMockServer(t, "", nil)
+
+	// Sample responses get the values of request fields, so they tell how
+	// requests were decoded.
+	responses, err := call(t, &Options{
+		Proto:     billingAPI,
+		Method:    "BillingService/GetInvoice",
+		Address:   httpAddress,
+		Data:      `{"customer_id": "c 1/2", "id": "7", "fields": ["a", "b"], "include_items": true}`,
+		Transport: TransportHTTP,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 || !strings.Contains(responses[0], `"customer_id":"c 1/2","id":"7"`) {
+		t.Errorf("got responses %v", responses)
+	}
+
+	_, err = call(t, &Options{
+		Proto:     billingAPI,
+		Method:    "BillingService/GetInvoice",
+		Address:   httpAddress + "/unknown",
+		Data:      `{"customer_id": "c1", "id": "7"}`,
+		Transport: TransportHTTP,
+	})
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found: no method bound to GET /unknown/v1/customers/c1/invoices/7") {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestLoadFixturesErrors(t *testing.T) {
+	api, err := LoadAPI(billingAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		fixture string
+		want    string
+	}{
+		{
+			name:    "unknown method",
+			fixture: `{"method": "BillingService/GetOrder"}`,
+			want:    "service 'BillingService' has no method 'GetOrder'",
+		},
+		{
+			name:    "invalid request",
+			fixture: `{"method": "BillingService/GetInvoice", "request": {"number": 1}}`,
+			want:    "invalid request",
+		},
+		{
+			name:    "invalid response",
+			fixture: `[{"method": "BillingService/GetInvoice"}, {"method": "BillingService/GetInvoice", "response": {"status": "DRAFT"}}]`,
+			want:    "fixture 2: invalid response",
+		},
+		{
+			name:    "several responses",
+			fixture: `{"method": "BillingService/GetInvoice", "responses": [{}, {}]}`,
+			want:    "method 'GetInvoice' returns a single response, got 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "fixture.json"), []byte(tt.fixture), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadFixtures(api, dir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTemplateMatch(t *testing.T) {
+	tests := []struct {
+		template string
+		path     string
+		want     map[string]string
+	}{
+		{
+			template: "/v1/customers/{customer_id}/invoices/{id}",
+			path:     "/v1/customers/c%201/invoices/42",
+			want:     map[string]string{"customer_id": "c 1", "id": "42"},
+		},
+		{
+			template: "/v1/{name=shelves/*}/books/{book.id}:publish",
+			path:     "/v1/shelves/s1/books/b1:publish",
+			want:     map[string]string{"name": "shelves/s1", "book.id": "b1"},
+		},
+		{
+			template: "/v1/files/{path=**}",
+			path:     "/v1/files/a/b/c.txt",
+			want:     map[string]string{"path": "a/b/c.txt"},
+		},
+		{
+			template: "/v1/customers/{customer_id}/invoices/{id}",
+			path:     "/v1/customers/c1/invoices",
+		},
+		{
+			template: "/v1/{name=shelves/*}/books/{book.id}:publish",
+			path:     "/v1/shelves/s1/books/b1",
+		},
+	}
+
+	for _, tt := range tests {
+		template, err := parsePathTemplate(tt.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, ok := template.Match(tt.path)
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v (%v), want %v", tt.path, got, ok, tt.want)
+		}
+	}
+}
+
+func TestSampleResponse(t *testing.T) {
+	api, err := LoadAPI(billingAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	method, err := api.Method("BillingService/GetInvoice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := dynamicpb.NewMessage(method.Descriptor.Input())
+	request.Set(method.Descriptor.Input().Fields().ByName("id"), protoreflect.ValueOfString("42"))
+
+	response := sampleResponse(method.Descriptor, request)
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := response.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if !response.ProtoReflect().Has(fields.Get(i)) {
+			t.Errorf("field %s not set: %s", fields.Get(i).Name(), data)
+		}
+	}
+	if got := response.ProtoReflect().Get(fields.ByName("id")).String(); got != "42" {
+		t.Errorf("id: got %q, want the request value", got)
+	}
+
+	// Samples are the same every time.
+	again, _ := protojson.MarshalOptions{UseProtoNames: true}.Marshal(sampleResponse(method.Descriptor, request))
+	if string(again) != string(data) {
+		t.Errorf("got different samples: %s and %s", data, again)
+	}
+}
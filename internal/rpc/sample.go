@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	// maxSampleDepth limits how deep nested messages of samples are filled.
+	maxSampleDepth = 4
+
+	// sampleTime is the time of sample timestamps: 2025-01-01T00:00:00Z.
+	sampleTime = 1735689600
+)
+
+// sampleResponse returns a response of a method filled with sample data.
+// Fields also found in the request, with the same name and type, get the
+// request values, so identifiers sent by clients come back to them.
+func sampleResponse(md protoreflect.MethodDescriptor, request proto.Message) proto.Message {
+	response := dynamicpb.NewMessage(md.Output())
+	fillSample(response, nil)
+
+	if request == nil {
+		return response
+	}
+
+	var (
+		in     = request.ProtoReflect()
+		fields = md.Output().Fields()
+	)
+	in.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		out := fields.ByName(fd.Name())
+		if out != nil && sameFieldType(fd, out) {
+			response.Set(out, value)
+		}
+
+		return true
+	})
+
+	return response
+}
+
+func sameFieldType(a, b protoreflect.FieldDescriptor) bool {
+	if a.Kind() != b.Kind() || a.Cardinality() != b.Cardinality() || a.IsMap() != b.IsMap() {
+		return false
+	}
+
+	switch a.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return a.Message().FullName() == b.Message().FullName()
+	case protoreflect.EnumKind:
+		return a.Enum().FullName() == b.Enum().FullName()
+	}
+
+	return true
+}
+
+// fillSample sets every field of a message with sample values. Only the
+// first field of each oneof is set, and messages already being filled,
+// i.e., recursive ones, are left empty.
+func fillSample(msg protoreflect.Message, parents []protoreflect.FullName) {
+	d := msg.Descriptor()
+	if len(parents) >= maxSampleDepth || slices.Contains(parents, d.FullName()) {
+		return
+	}
+	parents = append(parents, d.FullName())
+
+	switch d.FullName() {
+	case "google.protobuf.Any":
+		// Any messages can't be encoded as JSON without their types.
+		return
+	case "google.protobuf.Timestamp":
+		msg.Set(d.Fields().ByName("seconds"), protoreflect.ValueOfInt64(sampleTime))
+		return
+	}
+
+	fields := d.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if o := fd.ContainingOneof(); o != nil && !o.IsSynthetic() && o.Fields().Get(0) != fd {
+			continue
+		}
+
+		switch {
+		case fd.IsMap():
+			m := msg.Mutable(fd).Map()
+			m.Set(sampleScalar(fd.MapKey()).MapKey(), sampleValue(m.NewValue, fd.MapValue(), parents))
+
+		case fd.IsList():
+			list := msg.Mutable(fd).List()
+			list.Append(sampleValue(list.NewElement, fd, parents))
+
+		default:
+			newField := func() protoreflect.Value {
+				return msg.NewField(fd)
+			}
+			msg.Set(fd, sampleValue(newField, fd, parents))
+		}
+	}
+}
+
+// sampleValue returns a sample value of a field, using newValue to create
+// messages.
+func sampleValue(newValue func() protoreflect.Value, fd protoreflect.FieldDescriptor, parents []protoreflect.FullName) protoreflect.Value {
+	if fd.Message() == nil {
+		return sampleScalar(fd)
+	}
+
+	value := newValue()
+	fillSample(value.Message(), parents)
+	return value
+}
+
+// sampleScalar returns a sample value of a scalar or enum field, based on
+// its name when possible.
+func sampleScalar(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	name := string(fd.Name())
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(true)
+	case protoreflect.EnumKind:
+		// The first value is usually the unspecified one.
+		values := fd.Enum().Values()
+		if values.Len() > 1 {
+			return protoreflect.ValueOfEnum(values.Get(1).Number())
+		}
+		return protoreflect.ValueOfEnum(values.Get(0).Number())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(1)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(1)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(1)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(1)
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(1.5)
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(1.5)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(name))
+	}
+
+	switch {
+	case name == "id" || strings.HasSuffix(name, "_id"):
+		return protoreflect.ValueOfString(sampleID(string(fd.FullName())))
+	case strings.Contains(name, "email"):
+		return protoreflect.ValueOfString("user@example.com")
+	case strings.HasSuffix(name, "url") || strings.HasSuffix(name, "uri"):
+		return protoreflect.ValueOfString("https://example.com")
+	}
+
+	return protoreflect.ValueOfString(name)
+}
+
+// sampleID returns an UUID derived from a name, so samples are the same
+// every time.
+func sampleID(name string) string {
+	sum := sha1.Sum([]byte(name))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,237 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+	"github.com/mikros-dev/mikros-cli/internal/history"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+	"github.com/mikros-dev/mikros-cli/internal/process"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/template"
+	"github.com/mikros-dev/mikros-cli/internal/ui"
+)
+
+// KindPrefix prefixes the pack name in the kind of projects created from
+// packs, inside their generation manifests.
+const KindPrefix = "pack:"
+
+// NewOptions represents the options for the New command.
+type NewOptions struct {
+	// Path specifies where the project directory is created.
+	Path string
+
+	// Profile is the name of the profile whose answers history is used as
+	// default values.
+	Profile string
+}
+
+// New creates a new project from a template pack. It returns the path of the
+// created project.
+func New(cfg *settings.Settings, p *Pack, options *NewOptions) (string, error) {
+	hist, err := history.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load answers history: %w", err)
+	}
+
+	var (
+		previous    = hist.Get(options.Profile)
+		historyName = KindPrefix + p.Manifest.Name
+		answers     = make(map[string]interface{})
+	)
+
+	if s := p.Manifest.Survey; s != nil {
+		res, err := ui.RunFormFromSurvey(p.Manifest.Name, s, &ui.FormOptions{
+			Theme:      cfg.GetTheme(),
+			Accessible: cfg.UI.Accessible,
+			Defaults:   previous.PluginAnswers(historyName),
+		})
+		if err != nil {
+			return "", err
+		}
+		answers = res
+	}
+
+	path, err := generateProject(p, options, answers)
+	if err != nil {
+		return "", err
+	}
+
+	// Remember answers to use them as default values next time
+	previous.SetPluginAnswers(historyName, answers)
+	if err := hist.Write(); err != nil {
+		return "", fmt.Errorf("failed to write answers history: %w", err)
+	}
+
+	return path, nil
+}
+
+// Regenerate generates the project again inside path, without asking
+// anything or running the pack commands, using the answers recorded in its
+// generation manifest and the current pack templates. It returns the path
+// of the generated project.
+func Regenerate(cfg *settings.Settings, m *manifest.Manifest, path string) (string, error) {
+	if err := m.CheckAnswers(); err != nil {
+		return "", err
+	}
+
+	name := strings.TrimPrefix(m.Kind, KindPrefix)
+	p, err := Get(cfg, name)
+	if err != nil {
+		return "", err
+	}
+	if p == nil {
+		return "", fmt.Errorf("template pack '%s' is not installed", name)
+	}
+
+	return generateFiles(p, &NewOptions{Path: path}, m.Answers)
+}
+
+func generateProject(p *Pack, options *NewOptions, answers map[string]interface{}) (string, error) {
+	projectPath, err := generateFiles(p, options, answers)
+	if err != nil {
+		return "", err
+	}
+
+	// Switch to the project path so commands run inside it
+	cwd, err := fs.ChangeDir(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = os.Chdir(cwd)
+	}()
+
+	if err := runCommands(p, answers); err != nil {
+		return "", err
+	}
+
+	return projectPath, nil
+}
+
+// generateFiles creates the project directory with the pack templates and
+// the generation manifest.
+func generateFiles(p *Pack, options *NewOptions, answers map[string]interface{}) (string, error) {
+	projectPath, err := createProjectDirectory(p, options, answers)
+	if err != nil {
+		return "", err
+	}
+
+	m := manifest.New(KindPrefix + p.Manifest.Name)
+	m.SetAnswers(answers)
+
+	if err := createProjectTemplates(p, projectPath, answers, m); err != nil {
+		return "", err
+	}
+
+	if err := m.Write(projectPath); err != nil {
+		return "", err
+	}
+
+	return projectPath, nil
+}
+
+func createProjectDirectory(p *Pack, options *NewOptions, answers map[string]interface{}) (string, error) {
+	name, err := template.ParseBlock(p.Manifest.Directory, nil, answers)
+	if err != nil {
+		return "", fmt.Errorf("could not build project directory name: %w", err)
+	}
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid project directory name '%s'", name)
+	}
+
+	basePath := options.Path
+	if basePath == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		basePath = cwd
+	}
+
+	return fs.CreatePath(filepath.Join(basePath, name))
+}
+
+func createProjectTemplates(
+	p *Pack,
+	projectPath string,
+	answers map[string]interface{},
+	m *manifest.Manifest,
+) error {
+	var (
+		templates = make([]template.File, len(p.Manifest.Templates))
+		files     = make([]*template.Data, len(p.Manifest.Templates))
+	)
+
+	for i, t := range p.Manifest.Templates {
+		content, err := os.ReadFile(filepath.Join(p.Path, t.Source))
+		if err != nil {
+			return fmt.Errorf("could not read template: %w", err)
+		}
+
+		templates[i] = template.File{
+			Name:   filenameWithoutExtension(t.Source),
+			Output: t.Output,
+		}
+		files[i] = &template.Data{
+			FileName: t.Source,
+			Content:  content,
+			Context:  answers,
+		}
+	}
+
+	session, err := template.NewSessionFromData(&template.LoadOptions{
+		TemplatesToUse: templates,
+		Origin:         KindPrefix + p.Manifest.Name,
+	}, files)
+	if err != nil {
+		return err
+	}
+
+	generated, err := session.ExecuteTemplates(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, gen := range generated {
+		if err := gen.Write(projectPath); err != nil {
+			return err
+		}
+		m.AddGenerated(filepath.Join(projectPath, gen.Filename()), gen)
+	}
+
+	return nil
+}
+
+func filenameWithoutExtension(filename string) string {
+	return filename[:len(filename)-len(filepath.Ext(filename))]
+}
+
+func runCommands(p *Pack, answers map[string]interface{}) error {
+	for _, c := range p.Manifest.Commands {
+		args := make([]string, len(c.Args))
+		for i, arg := range c.Args {
+			a, err := template.ParseBlock(arg, nil, answers)
+			if err != nil {
+				return fmt.Errorf("could not build command arguments: %w", err)
+			}
+			args[i] = a
+		}
+
+		name := c.Name
+		if name == "" {
+			name = args[0]
+		}
+
+		if _, _, err := process.ExecContext(context.Background(), args...); err != nil {
+			return fmt.Errorf("command '%s' failed: %w", name, err)
+		}
+	}
+
+	return nil
+}
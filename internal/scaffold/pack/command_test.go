@@ -0,0 +1,123 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/golden"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func TestGenerateProject(t *testing.T) {
+	packsPath, err := filepath.Abs(filepath.Join("..", "..", "..", "examples", "packs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &settings.Settings{}
+	cfg.Paths.Packs = packsPath
+
+	p, err := Get(cfg, "go-library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil {
+		t.Fatal("go-library pack not found")
+	}
+
+	path, err := generateProject(p, &NewOptions{Path: t.TempDir()}, map[string]interface{}{
+		"name":   "string utils",
+		"module": "github.com/acme/string-utils",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.Compare(t, path, filepath.Join("testdata", "golden"))
+
+	// The manifest must be enough to generate the same project again.
+	m, err := manifest.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regenerated, err := Regenerate(cfg, m, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.CompareDirs(t, regenerated, path)
+}
+
+func TestGenerateSameSourceTwice(t *testing.T) {
+	dir := t.TempDir()
+	data := `{
+  "name": "config",
+  "directory": "config",
+  "templates": [
+    {"source": "config.tmpl", "output": "dev.env"},
+    {"source": "config.tmpl", "output": "prod.env"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, manifestFilename), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.tmpl"), []byte("NAME={{ .name }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := generateFiles(p, &NewOptions{Path: t.TempDir()}, map[string]interface{}{"name": "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"dev.env", "prod.env"} {
+		content, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "NAME=acme\n" {
+			t.Errorf("%s: got %q", name, content)
+		}
+	}
+}
+
+func TestLoadTemplateSources(t *testing.T) {
+	tests := []struct {
+		source string
+		valid  bool
+	}{
+		{source: "templates/main.go.tmpl", valid: true},
+		{source: "./README.md.tmpl", valid: true},
+		{source: "../secrets.txt"},
+		{source: "templates/../../secrets.txt"},
+		{source: "/etc/passwd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			dir := t.TempDir()
+			data := fmt.Sprintf(`{"name": "lib", "directory": "lib", "templates": [{"source": %q, "output": "main.go"}]}`, tt.source)
+			if err := os.WriteFile(filepath.Join(dir, manifestFilename), []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(dir)
+			if tt.valid {
+				if err != nil {
+					t.Errorf("got error %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "must be relative to the pack directory") {
+				t.Errorf("got error %v, want an invalid source", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/mikros-dev/mikros-cli/internal/fs"
+	"github.com/mikros-dev/mikros-cli/internal/plugin/survey"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+const (
+	manifestFilename = "pack.json"
+)
+
+// Pack is a template pack, i.e., a directory with a manifest describing how
+// to create a new project from the templates inside it.
+type Pack struct {
+	// Path is the pack directory.
+	Path     string
+	Manifest *Manifest
+}
+
+// Manifest describes a template pack.
+type Manifest struct {
+	// Name is the pack name, used as the project kind in the new command.
+	Name string `json:"name" validate:"required"`
+
+	// Description is the pack description presented when choosing a new
+	// project to create.
+	Description string `json:"description,omitempty"`
+
+	// Directory is the name of the project directory. It can use template
+	// expressions with the survey answers.
+	Directory string `json:"directory" validate:"required"`
+
+	// Survey holds the questions asked before generating the project. Their
+	// answers are the context of templates and commands.
+	Survey *survey.Survey `json:"survey,omitempty"`
+
+	// Templates are the files generated inside the project directory.
+	Templates []*Template `json:"templates" validate:"required,min=1,dive"`
+
+	// Commands are executed, in order, inside the project directory after
+	// all files are generated.
+	Commands []*Command `json:"commands,omitempty" validate:"dive"`
+}
+
+// Template is a file of the pack to be generated.
+type Template struct {
+	// Source is the template path, relative to the pack directory. It must
+	// be inside it.
+	Source string `json:"source" validate:"required"`
+
+	// Output is the generated file path, relative to the project directory.
+	// It can use template expressions with the survey answers.
+	Output string `json:"output" validate:"required"`
+}
+
+// Command is a command executed after the project is generated.
+type Command struct {
+	// Name is an optional description of the command.
+	Name string `json:"name,omitempty"`
+
+	// Args holds the command and its arguments. They can use template
+	// expressions with the survey answers.
+	Args []string `json:"args" validate:"required,min=1"`
+}
+
+// List returns all template packs available in the packs directory, sorted
+// by their names.
+func List(cfg *settings.Settings) ([]*Pack, error) {
+	var basePath = cfg.Paths.Packs
+	if !fs.FindPath(basePath) {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []*Pack
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(basePath, entry.Name())
+		if !fs.FindPath(filepath.Join(path, manifestFilename)) {
+			continue
+		}
+
+		p, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, p)
+	}
+
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Manifest.Name < packs[j].Manifest.Name
+	})
+
+	return packs, nil
+}
+
+// Get returns the template pack named name or nil if there is no such pack.
+func Get(cfg *settings.Settings, name string) (*Pack, error) {
+	packs, err := List(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range packs {
+		if p.Manifest.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Load loads the template pack from a directory.
+func Load(path string) (*Pack, error) {
+	data, err := os.ReadFile(filepath.Join(path, manifestFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("could not decode pack '%s' manifest: %w", path, err)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(manifest); err != nil {
+		return nil, fmt.Errorf("invalid pack '%s' manifest: %w", path, err)
+	}
+	for _, tpl := range manifest.Templates {
+		// Templates are read from the pack, so they can't be elsewhere.
+		if !filepath.IsLocal(filepath.FromSlash(tpl.Source)) {
+			return nil, fmt.Errorf("invalid pack '%s' manifest: template source '%s' must be relative to the pack directory", path, tpl.Source)
+		}
+	}
+
+	return &Pack{
+		Path:     path,
+		Manifest: &manifest,
+	}, nil
+}
+
+// Title returns the text used to present the pack.
+func (p *Pack) Title() string {
+	if p.Manifest.Description != "" {
+		return p.Manifest.Description
+	}
+
+	return p.Manifest.Name
+}
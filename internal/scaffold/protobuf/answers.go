@@ -1,23 +1,181 @@
 package protobuf
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
 // Answers represents the user-provided configuration for generating a protobuf
 // module.
 type Answers struct {
-	ServiceName string
-	Kind        string
-	Grpc        *GrpcAnswers
-	HTTP        *HTTPAnswers
+	ServiceName string       `json:"service_name"`
+	Kind        string       `json:"kind"`
+	Grpc        *GrpcAnswers `json:"grpc,omitempty"`
+	HTTP        *HTTPAnswers `json:"http,omitempty"`
 }
 
 // GrpcAnswers defines the properties for configuring gRPC service generation.
 type GrpcAnswers struct {
-	EntityName     string
-	UseDefaultRPCs bool
-	CustomRPCs     []string
+	EntityName     string                `json:"entity_name"`
+	EntityFields   []*EntityFieldAnswers `json:"entity_fields,omitempty"`
+	UseDefaultRPCs bool                  `json:"use_default_rpcs"`
+	CustomRPCs     []*CustomRPCAnswers   `json:"custom_rpcs,omitempty"`
+}
+
+// EntityFieldAnswers defines a field of the service main entity. Type is a
+// scalar type name when Kind is scalar, otherwise the name of the message or
+// enum. Qualified types, other than google well-known ones, must give the
+// file declaring them in Import. Identifier fields are the ones used to look
+// up the entity.
+type EntityFieldAnswers struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Type       string `json:"type"`
+	Import     string `json:"import,omitempty"`
+	Repeated   bool   `json:"repeated,omitempty"`
+	Optional   bool   `json:"optional,omitempty"`
+	Identifier bool   `json:"identifier,omitempty"`
+}
+
+// CustomRPCAnswers defines a custom RPC of a gRPC service. Empty message
+// names are derived from the RPC name.
+type CustomRPCAnswers struct {
+	Name          string `json:"name"`
+	RequestName   string `json:"request_name,omitempty"`
+	ResponseName  string `json:"response_name,omitempty"`
+	StreamingMode string `json:"streaming_mode,omitempty"`
 }
 
 // HTTPAnswers defines the properties for configuring HTTP service generation.
 type HTTPAnswers struct {
-	IsAuthenticated bool
-	RPCs            []*RPC
+	IsAuthenticated bool   `json:"is_authenticated"`
+	RPCs            []*RPC `json:"rpcs"`
+}
+
+// loadAnswers reads answers from a JSON file, so a module can be created
+// without running the forms.
+func loadAnswers(filename string) (*Answers, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var answers Answers
+	if err := json.Unmarshal(b, &answers); err != nil {
+		return nil, fmt.Errorf("could not parse answers file '%s': %w", filename, err)
+	}
+
+	if err := answers.validate(); err != nil {
+		return nil, fmt.Errorf("invalid answers file '%s': %w", filename, err)
+	}
+
+	// Properties derived from the answers in the forms.
+	if answers.HTTP != nil {
+		for _, rpc := range answers.HTTP.RPCs {
+			rpc.IsAuthenticated = answers.HTTP.IsAuthenticated
+			rpc.AuthArgMode = getAuthArgMode(rpc.HTTPMethod)
+		}
+	}
+
+	return &answers, nil
+}
+
+func (a *Answers) validate() error {
+	if a.ServiceName == "" {
+		return errors.New("service name cannot be empty")
+	}
+
+	switch a.Kind {
+	case "grpc":
+		if a.Grpc == nil {
+			return errors.New("missing grpc answers")
+		}
+
+		return a.Grpc.validate()
+
+	case "http":
+		if a.HTTP == nil || len(a.HTTP.RPCs) == 0 {
+			return errors.New("missing http RPCs")
+		}
+
+		for _, rpc := range a.HTTP.RPCs {
+			if rpc.Name == "" || rpc.HTTPEndpoint == "" {
+				return errors.New("http RPCs must have a name and an endpoint")
+			}
+			if !slices.Contains(httpMethods, rpc.HTTPMethod) {
+				return fmt.Errorf("unsupported method '%s' of RPC '%s'", rpc.HTTPMethod, rpc.Name)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported service kind '%s'", a.Kind)
+}
+
+func (g *GrpcAnswers) validate() error {
+	if g.EntityName == "" {
+		return errors.New("entity name cannot be empty")
+	}
+
+	var names []string
+	for _, f := range g.EntityFields {
+		if err := f.validate(); err != nil {
+			return err
+		}
+		if slices.Contains(names, f.Name) {
+			return fmt.Errorf("duplicated entity field '%s'", f.Name)
+		}
+		names = append(names, f.Name)
+	}
+
+	for _, rpc := range g.CustomRPCs {
+		if rpc.Name == "" {
+			return errors.New("custom RPC name cannot be empty")
+		}
+		if rpc.StreamingMode == "" {
+			rpc.StreamingMode = streamingModeUnary
+		}
+		if !slices.Contains(streamingModes, rpc.StreamingMode) {
+			return fmt.Errorf("unsupported streaming mode '%s' of RPC '%s'", rpc.StreamingMode, rpc.Name)
+		}
+	}
+
+	return nil
+}
+
+func (f *EntityFieldAnswers) validate() error {
+	if f.Name == "" {
+		return errors.New("entity field name cannot be empty")
+	}
+
+	switch f.Kind {
+	case fieldKindScalar:
+		if !slices.Contains(scalarTypes, f.Type) {
+			return fmt.Errorf("unsupported scalar type '%s' of field '%s'", f.Type, f.Name)
+		}
+
+	case fieldKindMessage, fieldKindEnum:
+		if f.Type == "" {
+			return fmt.Errorf("missing type of field '%s'", f.Name)
+		}
+		if needsImport(f.Type) && f.Import == "" {
+			return fmt.Errorf("missing import of type '%s' of field '%s'", f.Type, f.Name)
+		}
+
+	default:
+		return fmt.Errorf("unsupported kind '%s' of field '%s'", f.Kind, f.Name)
+	}
+
+	if f.Repeated && f.Optional {
+		return fmt.Errorf("field '%s' cannot be both repeated and optional", f.Name)
+	}
+	if f.Identifier && f.Repeated {
+		return fmt.Errorf("identifier field '%s' cannot be repeated", f.Name)
+	}
+
+	return nil
 }
@@ -12,18 +12,51 @@ import (
 	"github.com/mikros-dev/mikros-cli/internal/git"
 	"github.com/mikros-dev/mikros-cli/internal/settings"
 	"github.com/mikros-dev/mikros-cli/internal/template"
+	"github.com/mikros-dev/mikros-cli/internal/ui"
 )
 
 // NewOptions represents the options for the New command.
 type NewOptions struct {
 	Profile string
+
+	// AnswersFile is a JSON file with the answers to use instead of asking
+	// them.
+	AnswersFile string
+}
+
+// New initializes and generates required protobuf templates. It returns the
+// path where the files were generated.
+func New(cfg *settings.Settings, options *NewOptions) (string, error) {
+	if options.AnswersFile != "" {
+		answers, err := loadAnswers(options.AnswersFile)
+		if err != nil {
+			return "", err
+		}
+
+		return generateTemplates(cfg, answers, options)
+	}
+
+	// Every answer can be given by the answers file instead.
+	inputs := ui.NewInputs("service name", "service type", "entity name", "entity fields", "default CRUD RPCs", "custom RPCs", "authentication", "HTTP RPCs")
+	for i := range inputs {
+		inputs[i].Source = "--answers"
+	}
+	if err := ui.RequireInputs(inputs...); err != nil {
+		return "", err
+	}
+
+	answers, err := runForms(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return generateTemplates(cfg, answers, options)
 }
 
-// New initializes and generates required protobuf templates.
-func New(cfg *settings.Settings, options *NewOptions) error {
+func runForms(cfg *settings.Settings) (*Answers, error) {
 	name, kind, err := chooseService(cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	answers := &Answers{
@@ -35,11 +68,12 @@ func New(cfg *settings.Settings, options *NewOptions) error {
 	case "grpc":
 		form, err := runGrpcForm(cfg)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		answers.Grpc = &GrpcAnswers{
 			EntityName:     form.EntityName,
+			EntityFields:   form.EntityFields,
 			UseDefaultRPCs: form.DefaultRPCs,
 			CustomRPCs:     form.CustomRPCs,
 		}
@@ -47,7 +81,7 @@ func New(cfg *settings.Settings, options *NewOptions) error {
 	case "http":
 		isAuthenticated, rpcs, err := runHTTPForm(cfg)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		answers.HTTP = &HTTPAnswers{
@@ -56,23 +90,25 @@ func New(cfg *settings.Settings, options *NewOptions) error {
 		}
 	}
 
-	return generateTemplates(cfg, answers, options)
+	return answers, nil
 }
 
-func generateTemplates(cfg *settings.Settings, answers *Answers, options *NewOptions) error {
+func generateTemplates(cfg *settings.Settings, answers *Answers, options *NewOptions) (string, error) {
 	templateBasePath, err := getTemplatesBasePath(answers.ServiceName)
 	if err != nil {
-		return err
+		return "", err
 	}
 
+	searchPaths := template.NewSearchPaths(cfg.GetProfile(options.Profile).TemplatesDir)
+
 	if _, err := fs.CreatePath(templateBasePath); err != nil {
-		return err
+		return "", err
 	}
 
 	// Switch to the destination path to create template sources
 	cwd, err := fs.ChangeDir(templateBasePath)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	defer func() {
@@ -81,7 +117,11 @@ func generateTemplates(cfg *settings.Settings, answers *Answers, options *NewOpt
 		}
 	}()
 
-	return generateProtobufFiles(cfg, templateBasePath, answers, options)
+	if err := generateProtobufFiles(cfg, templateBasePath, answers, options, searchPaths); err != nil {
+		return "", err
+	}
+
+	return templateBasePath, nil
 }
 
 func getTemplatesBasePath(serviceName string) (string, error) {
@@ -147,7 +187,13 @@ func findProtoMainProjectPath(basePath, serviceName string, files []os.DirEntry)
 	return filepath.Join(projectPath, strings.ToLower(strcase.ToSnake(serviceName))), nil
 }
 
-func generateProtobufFiles(cfg *settings.Settings, basePath string, answers *Answers, options *NewOptions) error {
+func generateProtobufFiles(
+	cfg *settings.Settings,
+	basePath string,
+	answers *Answers,
+	options *NewOptions,
+	searchPaths template.SearchPaths,
+) error {
 	var (
 		filename = strings.ToLower(strcase.ToSnake(answers.ServiceName))
 		tplFiles = []template.File{
@@ -169,8 +215,9 @@ func generateProtobufFiles(cfg *settings.Settings, basePath string, answers *Ans
 
 	session, err := template.NewSessionFromFiles(&template.LoadOptions{
 		TemplatesToUse: tplFiles,
-		FilesBasePath:  "assets",
-	}, templateFiles)
+		FilesBasePath:  protobufTemplates.BasePath,
+		SearchPaths:    searchPaths.Set(protobufTemplates.Name),
+	}, protobufTemplates.Files)
 	if err != nil {
 		return err
 	}
@@ -186,17 +233,9 @@ func runTemplates(basePath string, session *template.Session, context interface{
 	}
 
 	for _, gen := range generated {
-		file, err := os.Create(filepath.Join(basePath, gen.Filename()))
-		if err != nil {
+		if err := gen.Write(basePath); err != nil {
 			return err
 		}
-
-		if _, err := file.Write(gen.Content()); err != nil {
-			_ = file.Close()
-			return err
-		}
-
-		_ = file.Close()
 	}
 
 	return nil
@@ -0,0 +1,162 @@
+package protobuf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/golden"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func TestGenerateProtobufFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		answers     *Answers
+		answersFile string
+	}{
+		{
+			name: "grpc",
+			answers: &Answers{
+				ServiceName: "billing",
+				Kind:        "grpc",
+				Grpc: &GrpcAnswers{
+					EntityName:     "invoice",
+					UseDefaultRPCs: true,
+					CustomRPCs: []*CustomRPCAnswers{
+						{
+							Name:          "send_invoice",
+							StreamingMode: streamingModeUnary,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "grpc-streaming",
+			answers: &Answers{
+				ServiceName: "billing",
+				Kind:        "grpc",
+				Grpc: &GrpcAnswers{
+					EntityName: "invoice",
+					CustomRPCs: []*CustomRPCAnswers{
+						{
+							Name:          "watch_invoices",
+							RequestName:   "watch_invoices_filter",
+							ResponseName:  "invoice_event",
+							StreamingMode: streamingModeServer,
+						},
+						{
+							Name:          "upload_items",
+							ResponseName:  "upload_summary",
+							StreamingMode: streamingModeClient,
+						},
+						{
+							Name:          "sync",
+							RequestName:   "invoice_event",
+							ResponseName:  "invoice_event",
+							StreamingMode: streamingModeBidi,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "grpc-entity",
+			answersFile: filepath.Join("testdata", "answers", "entity.json"),
+		},
+		{
+			name: "http",
+			answers: &Answers{
+				ServiceName: "billing_gateway",
+				Kind:        "http",
+				HTTP: &HTTPAnswers{
+					IsAuthenticated: true,
+					RPCs: []*RPC{
+						{
+							Name:            "GetInvoice",
+							HTTPMethod:      "get",
+							HTTPEndpoint:    "/invoices/{id}",
+							AuthArgMode:     getAuthArgMode("get"),
+							IsAuthenticated: true,
+						},
+						{
+							Name:            "CreateInvoice",
+							HTTPMethod:      "post",
+							HTTPEndpoint:    "/invoices",
+							AuthArgMode:     getAuthArgMode("post"),
+							IsAuthenticated: true,
+						},
+						{
+							Name:         "Health",
+							HTTPMethod:   "get",
+							HTTPEndpoint: "/health",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &settings.Settings{}
+			cfg.App.Project.ProtobufMonorepo = settings.ProtobufMonorepo{
+				RepositoryName: "protobuf-workspace",
+				ProjectName:    "services",
+				VcsPath:        "github.com/acme",
+			}
+			cfg.App.Project.Templates.Protobuf.CustomAuthName = "scopes"
+
+			answers := tt.answers
+			if tt.answersFile != "" {
+				a, err := loadAnswers(tt.answersFile)
+				if err != nil {
+					t.Fatal(err)
+				}
+				answers = a
+			}
+
+			path := t.TempDir()
+			if err := generateProtobufFiles(cfg, path, answers, &NewOptions{Profile: "default"}, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			golden.Compare(t, path, filepath.Join("testdata", "golden", tt.name))
+		})
+	}
+}
+
+func TestLoadAnswersErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"kind", `{"service_name": "billing", "kind": "rest"}`},
+		{"missing entity", `{"service_name": "billing", "kind": "grpc", "grpc": {}}`},
+		{"scalar type", `{"service_name": "billing", "kind": "grpc", "grpc": {"entity_name": "invoice",
+			"entity_fields": [{"name": "total", "kind": "scalar", "type": "decimal"}]}}`},
+		{"repeated identifier", `{"service_name": "billing", "kind": "grpc", "grpc": {"entity_name": "invoice",
+			"entity_fields": [{"name": "ids", "kind": "scalar", "type": "string", "repeated": true, "identifier": true}]}}`},
+		{"missing import", `{"service_name": "billing", "kind": "grpc", "grpc": {"entity_name": "invoice",
+			"entity_fields": [{"name": "total", "kind": "message", "type": "google.type.Money"}]}}`},
+		{"duplicated field", `{"service_name": "billing", "kind": "grpc", "grpc": {"entity_name": "invoice",
+			"entity_fields": [{"name": "id", "kind": "scalar", "type": "string"}, {"name": "id", "kind": "scalar", "type": "int64"}]}}`},
+		{"streaming mode", `{"service_name": "billing", "kind": "grpc", "grpc": {"entity_name": "invoice",
+			"custom_rpcs": [{"name": "sync", "streaming_mode": "duplex"}]}}`},
+		{"http method", `{"service_name": "billing", "kind": "http", "http": {"rpcs": [{"name": "Get", "method": "head", "endpoint": "/"}]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "answers.json")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := loadAnswers(filename); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -2,7 +2,21 @@ package protobuf
 
 import (
 	"embed"
+
+	"github.com/mikros-dev/mikros-cli/internal/template"
 )
 
 //go:embed assets/*.tmpl
 var templateFiles embed.FS
+
+var protobufTemplates = template.Set{
+	Name:     "protobuf",
+	Files:    templateFiles,
+	BasePath: "assets",
+}
+
+// TemplateSets returns the built-in template sets used to create protobuf
+// modules.
+func TemplateSets() []template.Set {
+	return []template.Set{protobufTemplates}
+}
@@ -0,0 +1,248 @@
+package protobuf
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Kinds of entity fields.
+const (
+	fieldKindScalar  = "scalar"
+	fieldKindMessage = "message"
+	fieldKindEnum    = "enum"
+)
+
+var (
+	// scalarTypes holds the protobuf scalar types supported by entity fields.
+	scalarTypes = []string{
+		"string", "bool", "bytes",
+		"int32", "int64", "uint32", "uint64",
+		"sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64",
+		"double", "float",
+	}
+
+	// wellKnownImports holds the files declaring google well-known types.
+	wellKnownImports = map[string]string{
+		"google.protobuf.Any":         "google/protobuf/any.proto",
+		"google.protobuf.Duration":    "google/protobuf/duration.proto",
+		"google.protobuf.Empty":       "google/protobuf/empty.proto",
+		"google.protobuf.FieldMask":   "google/protobuf/field_mask.proto",
+		"google.protobuf.Struct":      "google/protobuf/struct.proto",
+		"google.protobuf.Value":       "google/protobuf/struct.proto",
+		"google.protobuf.ListValue":   "google/protobuf/struct.proto",
+		"google.protobuf.Timestamp":   "google/protobuf/timestamp.proto",
+		"google.protobuf.BoolValue":   "google/protobuf/wrappers.proto",
+		"google.protobuf.BytesValue":  "google/protobuf/wrappers.proto",
+		"google.protobuf.DoubleValue": "google/protobuf/wrappers.proto",
+		"google.protobuf.FloatValue":  "google/protobuf/wrappers.proto",
+		"google.protobuf.Int32Value":  "google/protobuf/wrappers.proto",
+		"google.protobuf.Int64Value":  "google/protobuf/wrappers.proto",
+		"google.protobuf.StringValue": "google/protobuf/wrappers.proto",
+		"google.protobuf.UInt32Value": "google/protobuf/wrappers.proto",
+		"google.protobuf.UInt64Value": "google/protobuf/wrappers.proto",
+	}
+
+	// timestampFields are the fields every entity carries after the designed
+	// ones.
+	timestampFields = []string{"created_at", "updated_at", "deleted_at"}
+)
+
+// Field is a field of a generated message.
+type Field struct {
+	Name string
+	Kind string
+	Type string
+
+	// ImportPath is the file declaring Type, for qualified types that are
+	// not google well-known types.
+	ImportPath string
+
+	Number     int
+	Repeated   bool
+	Optional   bool
+	Identifier bool
+}
+
+// Declaration returns the field declaration inside its message.
+func (f *Field) Declaration() string {
+	var label string
+	if f.Repeated {
+		label = "repeated "
+	}
+	if f.Optional {
+		label = "optional "
+	}
+
+	return fmt.Sprintf("%s%s %s = %d;", label, f.Type, f.Name, f.Number)
+}
+
+// Import returns the file that must be imported to use the field type, if
+// any.
+func (f *Field) Import() string {
+	if imp, ok := wellKnownImports[f.Type]; ok {
+		return imp
+	}
+
+	return f.ImportPath
+}
+
+// needsImport returns true if a message or enum type is declared inside
+// another file that is not known, i.e., if it is a qualified type other
+// than google well-known ones.
+func needsImport(typeName string) bool {
+	_, wellKnown := wellKnownImports[typeName]
+	return strings.Contains(typeName, ".") && !wellKnown
+}
+
+// IsLocal returns true if the field type is a message or enum declared along
+// with the entity.
+func (f *Field) IsLocal() bool {
+	return f.Kind != fieldKindScalar && !strings.Contains(f.Type, ".")
+}
+
+// Enum is a placeholder enum declared along with the entity.
+type Enum struct {
+	Name string
+}
+
+// UnspecifiedValue returns the name of the enum zero value.
+func (e *Enum) UnspecifiedValue() string {
+	return strcase.ToScreamingSnake(e.Name) + "_UNSPECIFIED"
+}
+
+// Entity is the service main entity and the messages and enums its fields
+// need.
+type Entity struct {
+	Name     string
+	Fields   []*Field
+	Imports  []string
+	Messages []string
+	Enums    []*Enum
+}
+
+// generateEntity builds the entity from the designed fields, numbered in the
+// order they were given, followed by the timestamp fields. An "id" field is
+// added first when no identifier was designed.
+func generateEntity(name string, answers []*EntityFieldAnswers) *Entity {
+	var fields []*Field
+	if !slices.ContainsFunc(answers, func(f *EntityFieldAnswers) bool { return f.Identifier }) {
+		fields = append(fields, &Field{
+			Name:       "id",
+			Kind:       fieldKindScalar,
+			Type:       "string",
+			Identifier: true,
+		})
+	}
+
+	for _, answer := range answers {
+		fieldType := answer.Type
+		if answer.Kind != fieldKindScalar && !strings.Contains(fieldType, ".") {
+			fieldType = strcase.ToCamel(fieldType)
+		}
+
+		fields = append(fields, &Field{
+			Name:       strcase.ToSnake(answer.Name),
+			Kind:       answer.Kind,
+			Type:       fieldType,
+			ImportPath: answer.Import,
+			Repeated:   answer.Repeated,
+			Optional:   answer.Optional,
+			Identifier: answer.Identifier,
+		})
+	}
+
+	for _, timestamp := range timestampFields {
+		if !slices.ContainsFunc(fields, func(f *Field) bool { return f.Name == timestamp }) {
+			fields = append(fields, &Field{
+				Name: timestamp,
+				Kind: fieldKindMessage,
+				Type: "google.protobuf.Timestamp",
+			})
+		}
+	}
+
+	entity := &Entity{
+		Name:   strcase.ToCamel(name) + "Wire",
+		Fields: fields,
+	}
+
+	for i, f := range fields {
+		f.Number = i + 1
+
+		if imp := f.Import(); imp != "" && !slices.Contains(entity.Imports, imp) {
+			entity.Imports = append(entity.Imports, imp)
+		}
+		if !f.IsLocal() {
+			continue
+		}
+
+		switch f.Kind {
+		case fieldKindMessage:
+			if !slices.Contains(entity.Messages, f.Type) {
+				entity.Messages = append(entity.Messages, f.Type)
+			}
+		case fieldKindEnum:
+			if !slices.ContainsFunc(entity.Enums, func(e *Enum) bool { return e.Name == f.Type }) {
+				entity.Enums = append(entity.Enums, &Enum{Name: f.Type})
+			}
+		}
+	}
+
+	return entity
+}
+
+// IdentifierFields returns the fields used to look up the entity.
+func (e *Entity) IdentifierFields() []*Field {
+	return e.filterFields(func(f *Field) bool { return f.Identifier })
+}
+
+// DataFields returns the designed fields that are not identifiers.
+func (e *Entity) DataFields() []*Field {
+	return e.filterFields(func(f *Field) bool {
+		return !f.Identifier && !slices.Contains(timestampFields, f.Name)
+	})
+}
+
+// DesignedFields returns the entity fields, except the timestamp ones.
+func (e *Entity) DesignedFields() []*Field {
+	return e.filterFields(func(f *Field) bool {
+		return !slices.Contains(timestampFields, f.Name)
+	})
+}
+
+// APIImports returns the files the CRUD messages need to import.
+func (e *Entity) APIImports() []string {
+	var imports []string
+	for _, f := range e.DesignedFields() {
+		if imp := f.Import(); imp != "" && !slices.Contains(imports, imp) {
+			imports = append(imports, imp)
+		}
+	}
+
+	return imports
+}
+
+func (e *Entity) filterFields(match func(f *Field) bool) []*Field {
+	var fields []*Field
+	for _, f := range e.Fields {
+		if match(f) {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// messageBody returns the declarations of fields inside a message. Fields
+// keep the numbers they have in the entity.
+func messageBody(fields []*Field) string {
+	lines := make([]string, len(fields))
+	for i, f := range fields {
+		lines[i] = f.Declaration()
+	}
+
+	return strings.Join(lines, "\n  ")
+}
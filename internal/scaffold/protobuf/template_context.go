@@ -2,12 +2,27 @@ package protobuf
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/iancoleman/strcase"
 
 	"github.com/mikros-dev/mikros-cli/internal/settings"
 )
 
+// Streaming modes of custom RPCs.
+const (
+	streamingModeUnary  = "unary"
+	streamingModeServer = "server"
+	streamingModeClient = "client"
+	streamingModeBidi   = "bidi"
+)
+
+var (
+	streamingModes = []string{streamingModeUnary, streamingModeServer, streamingModeClient, streamingModeBidi}
+	httpMethods    = []string{"get", "post", "put", "delete", "patch"}
+)
+
 // Context represents the configuration and metadata for a generated service
 // or package.
 type Context struct {
@@ -16,9 +31,11 @@ type Context struct {
 	ServiceName      string
 	Version          string
 	EntityName       string
+	Entity           *Entity
 	CustomAuthName   string
 	RPCMethods       []*RPC
 	CustomRPCs       []*RPC
+	CustomMessages   []string
 	MainPackageName  string
 	RepositoryName   string
 	VCSProjectPrefix string
@@ -28,17 +45,19 @@ func generateTemplateContext(cfg *settings.Settings, answers *Answers, profileNa
 	var (
 		isAuthenticated bool
 		entityName      string
+		entity          *Entity
 		rpcs            []*RPC
 		customRPCs      []*RPC
-		profile         = projectProfile(cfg, profileName)
+		profile         = cfg.GetProfile(profileName)
 	)
 
 	if answers.Grpc != nil {
 		entityName = answers.Grpc.EntityName
-		customRPCs = generateRPCs(answers.Grpc.CustomRPCs)
+		entity = generateEntity(entityName, answers.Grpc.EntityFields)
+		customRPCs = generateCustomRPCs(answers.Grpc.CustomRPCs)
 
 		if answers.Grpc.UseDefaultRPCs {
-			rpcs = generateCRUDRPCs(entityName)
+			rpcs = generateCRUDRPCs(entity)
 		}
 	}
 	if answers.HTTP != nil {
@@ -52,29 +71,17 @@ func generateTemplateContext(cfg *settings.Settings, answers *Answers, profileNa
 		ServiceName:      answers.ServiceName,
 		Version:          "v0.1.0",
 		EntityName:       entityName,
+		Entity:           entity,
 		CustomAuthName:   profile.Project.Templates.Protobuf.CustomAuthName,
 		RPCMethods:       rpcs,
 		CustomRPCs:       customRPCs,
+		CustomMessages:   customMessages(rpcs, customRPCs),
 		MainPackageName:  profile.Project.ProtobufMonorepo.ProjectName,
 		RepositoryName:   profile.Project.ProtobufMonorepo.RepositoryName,
 		VCSProjectPrefix: profile.Project.ProtobufMonorepo.VcsPath,
 	}
 }
 
-func projectProfile(cfg *settings.Settings, profileName string) *settings.Profile {
-	profile := &cfg.App
-	if profileName == "default" {
-		return profile
-	}
-
-	d, ok := cfg.Profile[profileName]
-	if !ok {
-		return profile
-	}
-
-	return &d
-}
-
 // IsHTTPService returns true if the service is an HTTP service.
 func (c *Context) IsHTTPService() bool {
 	return c.httpService
@@ -85,63 +92,111 @@ func (c *Context) Extension() string {
 	return "proto"
 }
 
-// RPC represents a protobuf RPC.
+// RPC represents a protobuf RPC. Answers files only set the properties of
+// HTTP RPCs, the remaining ones are derived from them.
 type RPC struct {
-	IsAuthenticated bool
-	Name            string
-	HTTPMethod      string
-	HTTPEndpoint    string
-	AuthArgMode     string
-	RequestName     string
-	ResponseName    string
-	RequestBody     string
-	ResponseBody    string
+	IsAuthenticated bool   `json:"-"`
+	Name            string `json:"name"`
+	HTTPMethod      string `json:"method"`
+	HTTPEndpoint    string `json:"endpoint"`
+	AuthArgMode     string `json:"-"`
+	RequestName     string `json:"-"`
+	ResponseName    string `json:"-"`
+	RequestBody     string `json:"-"`
+	ResponseBody    string `json:"-"`
+	ClientStreaming bool   `json:"-"`
+	ServerStreaming bool   `json:"-"`
 }
 
-func generateCRUDRPCs(entityName string) []*RPC {
+// generateCRUDRPCs returns the default RPCs of an entity. Requests of RPCs
+// looking up the entity carry its identifier fields, while the ones creating
+// and updating it also carry the remaining designed fields.
+func generateCRUDRPCs(entity *Entity) []*RPC {
 	var (
-		messageName = strcase.ToCamel(entityName)
-		fieldName   = strcase.ToSnake(entityName)
+		messageName  = strings.TrimSuffix(entity.Name, "Wire")
+		responseBody = fmt.Sprintf("%s %s = 1;", entity.Name, strcase.ToSnake(messageName))
+		lookupBody   = messageBody(entity.IdentifierFields())
+		rpcs         = []*RPC{
+			{
+				Name:         fmt.Sprintf("Get%sByID", messageName),
+				RequestBody:  lookupBody,
+				ResponseBody: responseBody,
+			},
+			{
+				Name:         fmt.Sprintf("Create%s", messageName),
+				RequestBody:  messageBody(entity.DataFields()),
+				ResponseBody: responseBody,
+			},
+			{
+				Name:         fmt.Sprintf("Update%sByID", messageName),
+				RequestBody:  messageBody(entity.DesignedFields()),
+				ResponseBody: responseBody,
+			},
+			{
+				Name:         fmt.Sprintf("Delete%sByID", messageName),
+				RequestBody:  lookupBody,
+				ResponseBody: responseBody,
+			},
+		}
 	)
 
-	return []*RPC{
-		{
-			Name:         fmt.Sprintf("Get%sByID", messageName),
-			RequestBody:  "string id = 1;",
-			ResponseBody: fmt.Sprintf("%sWire %s = 1;", messageName, fieldName),
-		},
-		{
-			Name:         fmt.Sprintf("Create%s", messageName),
-			ResponseBody: fmt.Sprintf("%sWire %s = 1;", messageName, fieldName),
-		},
-		{
-			Name:         fmt.Sprintf("Update%sByID", messageName),
-			RequestBody:  "string id = 1;",
-			ResponseBody: fmt.Sprintf("%sWire %s = 1;", messageName, fieldName),
-		},
-		{
-			Name:         fmt.Sprintf("Delete%sByID", messageName),
-			RequestBody:  "string id = 1;",
-			ResponseBody: fmt.Sprintf("%sWire %s = 1;", messageName, fieldName),
-		},
+	for _, rpc := range rpcs {
+		rpc.RequestName = rpc.Name + "Request"
+		rpc.ResponseName = rpc.Name + "Response"
 	}
+
+	return rpcs
 }
 
-func generateRPCs(names []string) []*RPC {
+func generateCustomRPCs(answers []*CustomRPCAnswers) []*RPC {
 	var rpcs []*RPC
 
-	for _, name := range names {
-		messageName := strcase.ToCamel(name)
+	for _, answer := range answers {
+		var (
+			messageName  = strcase.ToCamel(answer.Name)
+			requestName  = strcase.ToCamel(answer.RequestName)
+			responseName = strcase.ToCamel(answer.ResponseName)
+		)
+		if requestName == "" {
+			requestName = messageName + "Request"
+		}
+		if responseName == "" {
+			responseName = messageName + "Response"
+		}
+
 		rpcs = append(rpcs, &RPC{
-			Name:         messageName,
-			RequestName:  messageName + "Request",
-			ResponseName: messageName + "Response",
+			Name:            messageName,
+			RequestName:     requestName,
+			ResponseName:    responseName,
+			ClientStreaming: answer.StreamingMode == streamingModeClient || answer.StreamingMode == streamingModeBidi,
+			ServerStreaming: answer.StreamingMode == streamingModeServer || answer.StreamingMode == streamingModeBidi,
 		})
 	}
 
 	return rpcs
 }
 
+// customMessages returns the messages used by custom RPCs, without
+// repeating the ones shared between them or already declared by the
+// default RPCs.
+func customMessages(defaultRPCs, customRPCs []*RPC) []string {
+	var declared []string
+	for _, rpc := range defaultRPCs {
+		declared = append(declared, rpc.RequestName, rpc.ResponseName)
+	}
+
+	var messages []string
+	for _, rpc := range customRPCs {
+		for _, name := range []string{rpc.RequestName, rpc.ResponseName} {
+			if !slices.Contains(declared, name) && !slices.Contains(messages, name) {
+				messages = append(messages, name)
+			}
+		}
+	}
+
+	return messages
+}
+
 // HasBody returns true if the RPC has a body.
 func (m *RPC) HasBody() bool {
 	return m.HTTPMethod == "post" || m.HTTPMethod == "put"
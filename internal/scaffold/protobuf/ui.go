@@ -1,7 +1,8 @@
 package protobuf
 
 import (
-	"strings"
+	"errors"
+	"slices"
 
 	"github.com/charmbracelet/huh"
 
@@ -34,7 +35,7 @@ func chooseService(cfg *settings.Settings) (string, string, error) {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "service name", "service type"); err != nil {
 		return "", "", err
 	}
 
@@ -42,17 +43,18 @@ func chooseService(cfg *settings.Settings) (string, string, error) {
 }
 
 type gRPCForm struct {
-	EntityName  string
-	DefaultRPCs bool
-	CustomRPCs  []string
+	EntityName   string
+	EntityFields []*EntityFieldAnswers
+	DefaultRPCs  bool
+	CustomRPCs   []*CustomRPCAnswers
 }
 
 func runGrpcForm(cfg *settings.Settings) (*gRPCForm, error) {
 	var (
-		entityName  string
-		defaultRPCs = true
-		customRPCs  []string
-		text        string
+		entityName    string
+		designFields  bool
+		defaultRPCs   = true
+		addCustomRPCs bool
 	)
 
 	form := huh.NewForm(
@@ -62,33 +64,273 @@ func runGrpcForm(cfg *settings.Settings) (*gRPCForm, error) {
 				Validate(ui.IsEmpty("entity name cannot be empty")).
 				Value(&entityName),
 
+			huh.NewConfirm().
+				Title("Do you want to design the entity fields?").
+				Description("Otherwise, the entity is identified by a string id field.").
+				Value(&designFields),
+
 			huh.NewConfirm().
 				Title("Use default CRUD RPCs for the service?").
 				Value(&defaultRPCs),
 
-			huh.NewText().
-				Title("Enter the custom RPCs names (one per line)").
-				Value(&text),
+			huh.NewConfirm().
+				Title("Do you want to add custom RPCs?").
+				Value(&addCustomRPCs),
 		),
 	).
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "entity name", "entity fields", "default CRUD RPCs", "custom RPCs"); err != nil {
 		return nil, err
 	}
 
-	if text != "" {
-		customRPCs = strings.Split(text, "\n")
+	var entityFields []*EntityFieldAnswers
+	if designFields {
+		fields, err := runEntityFieldsForm(cfg)
+		if err != nil {
+			return nil, err
+		}
+		entityFields = fields
+	}
+
+	var customRPCs []*CustomRPCAnswers
+	if addCustomRPCs {
+		rpcs, err := runGrpcRPCForm(cfg)
+		if err != nil {
+			return nil, err
+		}
+		customRPCs = rpcs
 	}
 
 	return &gRPCForm{
-		EntityName:  entityName,
-		DefaultRPCs: defaultRPCs,
-		CustomRPCs:  customRPCs,
+		EntityName:   entityName,
+		EntityFields: entityFields,
+		DefaultRPCs:  defaultRPCs,
+		CustomRPCs:   customRPCs,
 	}, nil
 }
 
+func runEntityFieldsForm(cfg *settings.Settings) ([]*EntityFieldAnswers, error) {
+	var fields []*EntityFieldAnswers
+
+	for {
+		field, err := promptSingleEntityField(cfg, fields)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+
+		continueAdding, err := confirmAddMoreField(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if !continueAdding {
+			break
+		}
+	}
+
+	return fields, nil
+}
+
+func promptSingleEntityField(cfg *settings.Settings, fields []*EntityFieldAnswers) (*EntityFieldAnswers, error) {
+	var (
+		scalarType = "string"
+		typeName   string
+		field      = &EntityFieldAnswers{
+			Kind: fieldKindScalar,
+		}
+	)
+
+	scalarOptions := make([]huh.Option[string], len(scalarTypes))
+	for i, t := range scalarTypes {
+		scalarOptions[i] = huh.NewOption(t, t)
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Name. Enter the field name:").
+				Value(&field.Name).
+				Validate(func(s string) error {
+					if s == "" {
+						return errors.New("field name cannot be empty")
+					}
+					if slices.ContainsFunc(fields, func(f *EntityFieldAnswers) bool { return f.Name == s }) {
+						return errors.New("field already exists")
+					}
+
+					return nil
+				}),
+
+			huh.NewSelect[string]().
+				Title("Kind. Select the kind of the field type:").
+				Options(
+					huh.NewOption("scalar", fieldKindScalar),
+					huh.NewOption("message", fieldKindMessage),
+					huh.NewOption("enum", fieldKindEnum),
+				).
+				Value(&field.Kind),
+		),
+
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Type. Select the field type:").
+				Options(scalarOptions...).
+				Value(&scalarType),
+		).WithHideFunc(func() bool {
+			return field.Kind != fieldKindScalar
+		}),
+
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Type. Enter the message or enum name:").
+				Description("Unqualified names are declared along with the entity.").
+				Value(&typeName).
+				Validate(ui.IsEmpty("type name cannot be empty")),
+		).WithHideFunc(func() bool {
+			return field.Kind == fieldKindScalar
+		}),
+
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Import. Enter the file declaring the type:").
+				Description("For example, google/type/money.proto.").
+				Value(&field.Import).
+				Validate(ui.IsEmpty("import cannot be empty")),
+		).WithHideFunc(func() bool {
+			return field.Kind == fieldKindScalar || !needsImport(typeName)
+		}),
+
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Is the field repeated?").
+				Value(&field.Repeated),
+
+			huh.NewConfirm().
+				Title("Is the field optional?").
+				Value(&field.Optional).
+				Validate(func(optional bool) error {
+					if optional && field.Repeated {
+						return errors.New("repeated fields cannot be optional")
+					}
+
+					return nil
+				}),
+
+			huh.NewConfirm().
+				Title("Does the field identify the entity?").
+				Value(&field.Identifier).
+				Validate(func(identifier bool) error {
+					if identifier && field.Repeated {
+						return errors.New("repeated fields cannot identify the entity")
+					}
+
+					return nil
+				}),
+		),
+	).
+		WithAccessible(cfg.UI.Accessible).
+		WithTheme(cfg.GetTheme())
+
+	if err := ui.RunForm(form, "field name", "field kind", "field type", "field import", "field modifiers"); err != nil {
+		return nil, err
+	}
+
+	field.Type = scalarType
+	if field.Kind != fieldKindScalar {
+		field.Type = typeName
+	}
+	if field.Kind == fieldKindScalar || !needsImport(typeName) {
+		field.Import = ""
+	}
+
+	return field, nil
+}
+
+func confirmAddMoreField(cfg *settings.Settings) (bool, error) {
+	var continueAdding bool
+
+	confirm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want to add a new field?").
+				Value(&continueAdding),
+		),
+	).
+		WithAccessible(cfg.UI.Accessible).
+		WithTheme(cfg.GetTheme())
+
+	if err := ui.RunForm(confirm, "add field confirmation"); err != nil {
+		return false, err
+	}
+
+	return continueAdding, nil
+}
+
+func runGrpcRPCForm(cfg *settings.Settings) ([]*CustomRPCAnswers, error) {
+	var rpcs []*CustomRPCAnswers
+
+	for {
+		rpc, err := promptSingleGrpcRPC(cfg)
+		if err != nil {
+			return nil, err
+		}
+		rpcs = append(rpcs, rpc)
+
+		continueAdding, err := confirmAddMoreRPC(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if !continueAdding {
+			break
+		}
+	}
+
+	return rpcs, nil
+}
+
+func promptSingleGrpcRPC(cfg *settings.Settings) (*CustomRPCAnswers, error) {
+	rpc := &CustomRPCAnswers{
+		StreamingMode: streamingModeUnary,
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Name. Enter the RPC name:").
+				Value(&rpc.Name).
+				Validate(ui.IsEmpty("RPC name cannot be empty")),
+
+			huh.NewInput().
+				Title("Request. Enter the request message name (empty for <Name>Request):").
+				Value(&rpc.RequestName),
+
+			huh.NewInput().
+				Title("Response. Enter the response message name (empty for <Name>Response):").
+				Value(&rpc.ResponseName),
+
+			huh.NewSelect[string]().
+				Title("Streaming. Select how messages are exchanged:").
+				Options(
+					huh.NewOption("unary (no streaming)", streamingModeUnary),
+					huh.NewOption("server streaming", streamingModeServer),
+					huh.NewOption("client streaming", streamingModeClient),
+					huh.NewOption("bidirectional streaming", streamingModeBidi),
+				).
+				Value(&rpc.StreamingMode),
+		),
+	).
+		WithAccessible(cfg.UI.Accessible).
+		WithTheme(cfg.GetTheme())
+
+	if err := ui.RunForm(form, "RPC name", "RPC request", "RPC response", "RPC streaming mode"); err != nil {
+		return nil, err
+	}
+
+	return rpc, nil
+}
+
 func runHTTPForm(cfg *settings.Settings) (bool, []*RPC, error) {
 	var isAuthenticated bool
 
@@ -102,8 +344,8 @@ func runHTTPForm(cfg *settings.Settings) (bool, []*RPC, error) {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
-		return false, nil, nil
+	if err := ui.RunForm(form, "authentication"); err != nil {
+		return false, nil, err
 	}
 
 	rpcs, err := runHTTPRPCForm(cfg, isAuthenticated)
@@ -170,7 +412,7 @@ func promptSingleHTTPRPC(cfg *settings.Settings, isAuthenticated bool) (*RPC, er
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "RPC name", "RPC method", "RPC endpoint"); err != nil {
 		return nil, err
 	}
 
@@ -196,7 +438,7 @@ func confirmAddMoreRPC(cfg *settings.Settings) (bool, error) {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := confirm.Run(); err != nil {
+	if err := ui.RunForm(confirm, "add RPC confirmation"); err != nil {
 		return false, err
 	}
 
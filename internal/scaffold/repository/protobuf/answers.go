@@ -5,13 +5,13 @@ import (
 )
 
 type surveyAnswers struct {
-	RepositoryName string `survey:"repository_name"`
-	ProjectName    string `survey:"project_name"`
-	VcsPath        string `survey:"vcs_path"`
+	RepositoryName string `survey:"repository_name" json:"repository_name"`
+	ProjectName    string `survey:"project_name" json:"project_name"`
+	VcsPath        string `survey:"vcs_path" json:"vcs_path"`
 }
 
 func newSurveyAnswers(cfg *settings.Settings, profileName string) *surveyAnswers {
-	profile := surveyProfile(cfg, profileName)
+	profile := cfg.GetProfile(profileName)
 
 	return &surveyAnswers{
 		RepositoryName: profile.Project.ProtobufMonorepo.RepositoryName,
@@ -19,17 +19,3 @@ func newSurveyAnswers(cfg *settings.Settings, profileName string) *surveyAnswers
 		VcsPath:        profile.Project.ProtobufMonorepo.VcsPath,
 	}
 }
-
-func surveyProfile(cfg *settings.Settings, profile string) *settings.Profile {
-	defaultValue := &cfg.App
-	if profile == "default" {
-		return defaultValue
-	}
-
-	d, ok := cfg.Profile[profile]
-	if !ok {
-		return defaultValue
-	}
-
-	return &d
-}
@@ -1,6 +1,8 @@
 package protobuf
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +13,7 @@ import (
 	"github.com/mikros-dev/mikros-cli/internal/fs"
 	"github.com/mikros-dev/mikros-cli/internal/git"
 	"github.com/mikros-dev/mikros-cli/internal/golang"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
 	"github.com/mikros-dev/mikros-cli/internal/settings"
 	"github.com/mikros-dev/mikros-cli/internal/template"
 )
@@ -22,26 +25,59 @@ type NewOptions struct {
 	Profile string
 }
 
-// New creates a new protobuf repository based on the provided settings.
-func New(cfg *settings.Settings, options *NewOptions) error {
+// New creates a new protobuf repository based on the provided settings. It
+// returns the path of the created repository.
+func New(cfg *settings.Settings, options *NewOptions) (string, error) {
 	answers, err := runSurvey(cfg, options.Profile)
+	if err != nil {
+		return "", err
+	}
+
+	searchPaths := template.NewSearchPaths(cfg.GetProfile(options.Profile).TemplatesDir)
+
+	return generateProject(options, answers, searchPaths)
+}
+
+// Regenerate generates the repository again inside path, without asking
+// anything, using the answers recorded in its generation manifest and the
+// current templates. It returns the path of the generated repository.
+func Regenerate(m *manifest.Manifest, path string, searchPaths template.SearchPaths) (string, error) {
+	answers := &surveyAnswers{}
+	if err := decodeAnswers(m.Answers, answers); err != nil {
+		return "", err
+	}
+
+	return generateProject(&NewOptions{
+		NoVCS: true,
+		Path:  path,
+	}, answers, searchPaths)
+}
+
+func decodeAnswers(recorded map[string]interface{}, answers *surveyAnswers) error {
+	b, err := json.Marshal(recorded)
 	if err != nil {
 		return err
 	}
+	if err := json.Unmarshal(b, answers); err != nil {
+		return fmt.Errorf("invalid manifest answers: %w", err)
+	}
+	if answers.RepositoryName == "" {
+		return errors.New("manifest does not have the repository name")
+	}
 
-	return generateProject(options, answers)
+	return nil
 }
 
-func generateProject(options *NewOptions, answers *surveyAnswers) error {
+func generateProject(options *NewOptions, answers *surveyAnswers, searchPaths template.SearchPaths) (string, error) {
 	repositoryPath, err := createProjectDirectory(options, answers.RepositoryName)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Switch to the destination path so we can work inside
 	cwd, err := fs.ChangeDir(repositoryPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	defer func() {
@@ -50,23 +86,34 @@ func generateProject(options *NewOptions, answers *surveyAnswers) error {
 		}
 	}()
 
+	m := manifest.New("protobuf-monorepo")
+	m.SetAnswers(map[string]interface{}{
+		"repository_name": answers.RepositoryName,
+		"project_name":    answers.ProjectName,
+		"vcs_path":        answers.VcsPath,
+	})
+
 	// Notice that, starting from here, we're inside the project directory.
-	if err := createProjectTemplates(answers, repositoryPath); err != nil {
-		return err
+	if err := createProjectTemplates(answers, repositoryPath, searchPaths, m); err != nil {
+		return "", err
+	}
+
+	if err := m.Write(repositoryPath); err != nil {
+		return "", err
 	}
 
 	// Initialize go module for the new repository
 	if err := golang.ModInit(projectModuleName(answers)); err != nil {
-		return err
+		return "", err
 	}
 
 	if !options.NoVCS {
 		if _, err := git.Init(); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	return nil
+	return repositoryPath, nil
 }
 
 func createProjectDirectory(options *NewOptions, repositoryName string) (string, error) {
@@ -75,11 +122,7 @@ func createProjectDirectory(options *NewOptions, repositoryName string) (string,
 		return "", err
 	}
 
-	if _, err := fs.CreatePath(p); err != nil {
-		return "", err
-	}
-
-	return p, nil
+	return fs.CreatePath(p)
 }
 
 func projectBasePath(options *NewOptions, repositoryName string) (string, error) {
@@ -101,25 +144,34 @@ func projectModuleName(answers *surveyAnswers) string {
 	return fmt.Sprintf("%s/%s", answers.VcsPath, strings.ToLower(strcase.ToKebab(answers.RepositoryName)))
 }
 
-func createProjectTemplates(answer *surveyAnswers, repositoryPath string) error {
+func createProjectTemplates(
+	answer *surveyAnswers,
+	repositoryPath string,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	tplCtx := &TemplateContext{
 		MainPackageName:  answer.ProjectName,
 		RepositoryName:   answer.RepositoryName,
 		VCSProjectPrefix: answer.VcsPath,
 	}
 
-	if err := createProjectRootTemplates(tplCtx); err != nil {
+	if err := createProjectRootTemplates(tplCtx, searchPaths, m); err != nil {
 		return err
 	}
 
-	if err := createProjectScriptsTemplates(repositoryPath, tplCtx); err != nil {
+	if err := createProjectScriptsTemplates(repositoryPath, tplCtx, searchPaths, m); err != nil {
 		return err
 	}
 
-	return createProjectProtoTemplates(repositoryPath, tplCtx)
+	return createProjectProtoTemplates(repositoryPath, tplCtx, searchPaths, m)
 }
 
-func createProjectRootTemplates(tplCtx *TemplateContext) error {
+func createProjectRootTemplates(
+	tplCtx *TemplateContext,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	templates := []template.File{
 		{
 			Name: "buf.gen.yaml",
@@ -137,23 +189,23 @@ func createProjectRootTemplates(tplCtx *TemplateContext) error {
 
 	session, err := template.NewSessionFromFiles(&template.LoadOptions{
 		TemplatesToUse: templates,
-		FilesBasePath:  "assets/root",
-	}, rootTemplateFiles)
+		FilesBasePath:  rootTemplates.BasePath,
+		SearchPaths:    searchPaths.Set(rootTemplates.Name),
+	}, rootTemplates.Files)
 	if err != nil {
 		return err
 	}
 
-	return runTemplates(session, tplCtx)
+	return runTemplates(session, tplCtx, m)
 }
 
-func createProjectScriptsTemplates(repositoryPath string, tplCtx *TemplateContext) error {
+func createProjectScriptsTemplates(
+	repositoryPath string,
+	tplCtx *TemplateContext,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	templates := []template.File{
-		{
-			Name: "generate.sh",
-		},
-		{
-			Name: "go.sh",
-		},
 		{
 			Name: "setup.sh",
 		},
@@ -176,13 +228,14 @@ func createProjectScriptsTemplates(repositoryPath string, tplCtx *TemplateContex
 
 	session, err := template.NewSessionFromFiles(&template.LoadOptions{
 		TemplatesToUse: templates,
-		FilesBasePath:  "assets/scripts",
-	}, scriptsTemplateFiles)
+		FilesBasePath:  scriptsTemplates.BasePath,
+		SearchPaths:    searchPaths.Set(scriptsTemplates.Name),
+	}, scriptsTemplates.Files)
 	if err != nil {
 		return err
 	}
 
-	if err := runTemplates(session, tplCtx); err != nil {
+	if err := runTemplates(session, tplCtx, m); err != nil {
 		return err
 	}
 
@@ -195,7 +248,12 @@ func createProjectScriptsTemplates(repositoryPath string, tplCtx *TemplateContex
 	return nil
 }
 
-func createProjectProtoTemplates(repositoryPath string, tplCtx *TemplateContext) error {
+func createProjectProtoTemplates(
+	repositoryPath string,
+	tplCtx *TemplateContext,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	templates := []template.File{
 		{
 			Name: "example.proto",
@@ -219,33 +277,27 @@ func createProjectProtoTemplates(repositoryPath string, tplCtx *TemplateContext)
 
 	session, err := template.NewSessionFromFiles(&template.LoadOptions{
 		TemplatesToUse: templates,
-		FilesBasePath:  "assets/proto",
-	}, protoTemplateFiles)
+		FilesBasePath:  protoTemplates.BasePath,
+		SearchPaths:    searchPaths.Set(protoTemplates.Name),
+	}, protoTemplates.Files)
 	if err != nil {
 		return err
 	}
 
-	return runTemplates(session, tplCtx)
+	return runTemplates(session, tplCtx, m)
 }
 
-func runTemplates(session *template.Session, context interface{}) error {
+func runTemplates(session *template.Session, context interface{}, m *manifest.Manifest) error {
 	generated, err := session.ExecuteTemplates(context)
 	if err != nil {
 		return err
 	}
 
 	for _, gen := range generated {
-		file, err := os.Create(gen.Filename())
-		if err != nil {
+		if err := gen.Write(""); err != nil {
 			return err
 		}
-
-		if _, err := file.Write(gen.Content()); err != nil {
-			_ = file.Close()
-			return err
-		}
-
-		_ = file.Close()
+		m.AddGenerated(gen.Filename(), gen)
 	}
 
 	return nil
@@ -0,0 +1,36 @@
+package protobuf
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/golden"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+)
+
+func TestGenerateProject(t *testing.T) {
+	path, err := generateProject(&NewOptions{
+		NoVCS: true,
+		Path:  t.TempDir(),
+	}, &surveyAnswers{
+		RepositoryName: "protobuf-workspace",
+		ProjectName:    "services",
+		VcsPath:        "github.com/acme",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.Compare(t, path, filepath.Join("testdata", "golden"))
+
+	// The manifest must be enough to generate the same repository again.
+	m, err := manifest.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regenerated, err := Regenerate(m, t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.CompareDirs(t, regenerated, path)
+}
@@ -2,6 +2,8 @@ package protobuf
 
 import (
 	"embed"
+
+	"github.com/mikros-dev/mikros-cli/internal/template"
 )
 
 //go:embed assets/proto/*.tmpl
@@ -12,3 +14,29 @@ var rootTemplateFiles embed.FS
 
 //go:embed assets/scripts/*.tmpl
 var scriptsTemplateFiles embed.FS
+
+var (
+	protoTemplates = template.Set{
+		Name:     "repository/protobuf/proto",
+		Files:    protoTemplateFiles,
+		BasePath: "assets/proto",
+	}
+
+	rootTemplates = template.Set{
+		Name:     "repository/protobuf/root",
+		Files:    rootTemplateFiles,
+		BasePath: "assets/root",
+	}
+
+	scriptsTemplates = template.Set{
+		Name:     "repository/protobuf/scripts",
+		Files:    scriptsTemplateFiles,
+		BasePath: "assets/scripts",
+	}
+)
+
+// TemplateSets returns the built-in template sets used to create protobuf
+// repositories.
+func TemplateSets() []template.Set {
+	return []template.Set{rootTemplates, scriptsTemplates, protoTemplates}
+}
@@ -30,7 +30,7 @@ func runSurvey(cfg *settings.Settings, profile string) (*surveyAnswers, error) {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "repository name", "project name", "VCS path prefix"); err != nil {
 		return nil, err
 	}
 
@@ -1,5 +1,5 @@
 package service
 
 type surveyAnswers struct {
-	RepositoryName string
+	RepositoryName string `json:"repository_name"`
 }
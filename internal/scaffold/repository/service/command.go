@@ -1,6 +1,9 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,37 +12,72 @@ import (
 
 	"github.com/mikros-dev/mikros-cli/internal/fs"
 	"github.com/mikros-dev/mikros-cli/internal/git"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
 	"github.com/mikros-dev/mikros-cli/internal/settings"
 	"github.com/mikros-dev/mikros-cli/internal/template"
 )
 
 // NewOptions represents the options for the New command.
 type NewOptions struct {
-	NoVCS bool
-	Path  string
+	NoVCS   bool
+	Path    string
+	Profile string
 }
 
 // New creates a new project based on the provided settings and options,
-// running a survey and generating the project files.
-func New(cfg *settings.Settings, options *NewOptions) error {
+// running a survey and generating the project files. It returns the path of
+// the created repository.
+func New(cfg *settings.Settings, options *NewOptions) (string, error) {
 	answers, err := runSurvey(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	searchPaths := template.NewSearchPaths(cfg.GetProfile(options.Profile).TemplatesDir)
+
+	return generateProject(options, answers, searchPaths)
+}
+
+// Regenerate generates the repository again inside path, without asking
+// anything, using the answers recorded in its generation manifest and the
+// current templates. It returns the path of the generated repository.
+func Regenerate(m *manifest.Manifest, path string, searchPaths template.SearchPaths) (string, error) {
+	answers := &surveyAnswers{}
+	if err := decodeAnswers(m.Answers, answers); err != nil {
+		return "", err
+	}
+
+	return generateProject(&NewOptions{
+		NoVCS: true,
+		Path:  path,
+	}, answers, searchPaths)
+}
+
+func decodeAnswers(recorded map[string]interface{}, answers *surveyAnswers) error {
+	b, err := json.Marshal(recorded)
 	if err != nil {
 		return err
 	}
+	if err := json.Unmarshal(b, answers); err != nil {
+		return fmt.Errorf("invalid manifest answers: %w", err)
+	}
+	if answers.RepositoryName == "" {
+		return errors.New("manifest does not have the repository name")
+	}
 
-	return generateProject(options, answers)
+	return nil
 }
 
-func generateProject(options *NewOptions, answers *surveyAnswers) error {
+func generateProject(options *NewOptions, answers *surveyAnswers, searchPaths template.SearchPaths) (string, error) {
 	repositoryPath, err := createProjectDirectory(options, answers.RepositoryName)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Switch to the destination path so we can work inside
 	cwd, err := fs.ChangeDir(repositoryPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	defer func() {
@@ -48,18 +86,27 @@ func generateProject(options *NewOptions, answers *surveyAnswers) error {
 		}
 	}()
 
+	m := manifest.New("services-monorepo")
+	m.SetAnswers(map[string]interface{}{
+		"repository_name": answers.RepositoryName,
+	})
+
 	// Notice that, starting from here, we're inside the project directory.
-	if err := createProjectTemplates(answers, repositoryPath); err != nil {
-		return err
+	if err := createProjectTemplates(answers, repositoryPath, searchPaths, m); err != nil {
+		return "", err
+	}
+
+	if err := m.Write(repositoryPath); err != nil {
+		return "", err
 	}
 
 	if !options.NoVCS {
 		if _, err := git.Init(); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	return nil
+	return repositoryPath, nil
 }
 
 func createProjectDirectory(options *NewOptions, repositoryName string) (string, error) {
@@ -68,11 +115,7 @@ func createProjectDirectory(options *NewOptions, repositoryName string) (string,
 		return "", err
 	}
 
-	if _, err := fs.CreatePath(p); err != nil {
-		return "", err
-	}
-
-	return p, nil
+	return fs.CreatePath(p)
 }
 
 func projectBasePath(options *NewOptions, repositoryName string) (string, error) {
@@ -90,19 +133,28 @@ func projectBasePath(options *NewOptions, repositoryName string) (string, error)
 	return filepath.Join(options.Path, name), nil
 }
 
-func createProjectTemplates(answer *surveyAnswers, repositoryPath string) error {
+func createProjectTemplates(
+	answer *surveyAnswers,
+	repositoryPath string,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	tplCtx := &TemplateContext{
 		RepositoryName: answer.RepositoryName,
 	}
 
-	if err := createProjectRootTemplates(tplCtx); err != nil {
+	if err := createProjectRootTemplates(tplCtx, searchPaths, m); err != nil {
 		return err
 	}
 
-	return createProjectScriptsTemplates(repositoryPath, tplCtx)
+	return createProjectScriptsTemplates(repositoryPath, tplCtx, searchPaths, m)
 }
 
-func createProjectRootTemplates(tplCtx *TemplateContext) error {
+func createProjectRootTemplates(
+	tplCtx *TemplateContext,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	templates := []template.File{
 		{
 			Name: "Makefile",
@@ -117,16 +169,22 @@ func createProjectRootTemplates(tplCtx *TemplateContext) error {
 
 	session, err := template.NewSessionFromFiles(&template.LoadOptions{
 		TemplatesToUse: templates,
-		FilesBasePath:  "assets/root",
-	}, rootFiles)
+		FilesBasePath:  rootTemplates.BasePath,
+		SearchPaths:    searchPaths.Set(rootTemplates.Name),
+	}, rootTemplates.Files)
 	if err != nil {
 		return err
 	}
 
-	return runTemplates(session, tplCtx)
+	return runTemplates(session, tplCtx, m)
 }
 
-func createProjectScriptsTemplates(repositoryPath string, tplCtx *TemplateContext) error {
+func createProjectScriptsTemplates(
+	repositoryPath string,
+	tplCtx *TemplateContext,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	templates := []template.File{
 		{
 			Name: "badges.sh",
@@ -162,13 +220,14 @@ func createProjectScriptsTemplates(repositoryPath string, tplCtx *TemplateContex
 
 	session, err := template.NewSessionFromFiles(&template.LoadOptions{
 		TemplatesToUse: templates,
-		FilesBasePath:  "assets/scripts",
-	}, scriptFiles)
+		FilesBasePath:  scriptsTemplates.BasePath,
+		SearchPaths:    searchPaths.Set(scriptsTemplates.Name),
+	}, scriptsTemplates.Files)
 	if err != nil {
 		return err
 	}
 
-	if err := runTemplates(session, tplCtx); err != nil {
+	if err := runTemplates(session, tplCtx, m); err != nil {
 		return err
 	}
 
@@ -181,24 +240,17 @@ func createProjectScriptsTemplates(repositoryPath string, tplCtx *TemplateContex
 	return nil
 }
 
-func runTemplates(session *template.Session, context interface{}) error {
+func runTemplates(session *template.Session, context interface{}, m *manifest.Manifest) error {
 	generated, err := session.ExecuteTemplates(context)
 	if err != nil {
 		return err
 	}
 
 	for _, gen := range generated {
-		file, err := os.Create(gen.Filename())
-		if err != nil {
+		if err := gen.Write(""); err != nil {
 			return err
 		}
-
-		if _, err := file.Write(gen.Content()); err != nil {
-			_ = file.Close()
-			return err
-		}
-
-		_ = file.Close()
+		m.AddGenerated(gen.Filename(), gen)
 	}
 
 	return nil
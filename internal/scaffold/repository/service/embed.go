@@ -2,6 +2,8 @@ package service
 
 import (
 	"embed"
+
+	"github.com/mikros-dev/mikros-cli/internal/template"
 )
 
 //go:embed assets/root/*.tmpl
@@ -9,3 +11,23 @@ var rootFiles embed.FS
 
 //go:embed assets/scripts/*.tmpl
 var scriptFiles embed.FS
+
+var (
+	rootTemplates = template.Set{
+		Name:     "repository/service/root",
+		Files:    rootFiles,
+		BasePath: "assets/root",
+	}
+
+	scriptsTemplates = template.Set{
+		Name:     "repository/service/scripts",
+		Files:    scriptFiles,
+		BasePath: "assets/scripts",
+	}
+)
+
+// TemplateSets returns the built-in template sets used to create services
+// repositories.
+func TemplateSets() []template.Set {
+	return []template.Set{rootTemplates, scriptsTemplates}
+}
@@ -20,7 +20,7 @@ func runSurvey(cfg *settings.Settings) (*surveyAnswers, error) {
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	if err := form.Run(); err != nil {
+	if err := ui.RunForm(form, "repository name"); err != nil {
 		return nil, err
 	}
 
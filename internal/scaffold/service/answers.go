@@ -1,15 +1,23 @@
 package service
 
 import (
+	"fmt"
+	"path"
+	"strings"
+
 	"github.com/creasty/defaults"
+	"github.com/iancoleman/strcase"
 	"github.com/mikros-dev/mikros-cli/internal/protobuf"
 	"github.com/mikros-dev/mikros/components/definition"
+	"golang.org/x/mod/module"
 
+	"github.com/mikros-dev/mikros-cli/internal/history"
 	"github.com/mikros-dev/mikros-cli/internal/template"
 )
 
 type surveyAnswers struct {
 	Name      string
+	Module    string
 	Type      string
 	Language  string
 	Version   string `default:"v0.1.0"`
@@ -19,18 +27,39 @@ type surveyAnswers struct {
 	HTTPType  string
 
 	serviceAnswers     map[string]interface{}
+	featureAnswers     map[string]*featureSurveyAnswers
 	featureDefinitions map[string]*surveyAnswersDefinitions
 	serviceDefinitions *surveyAnswersDefinitions
+	history            *history.Answers
+}
+
+// featureSurveyAnswers holds the answers given to a feature survey, keyed by
+// the feature UI name inside surveyAnswers.
+type featureSurveyAnswers struct {
+	name    string
+	answers map[string]interface{}
 }
 
-func newSurveyAnswers(protoFilename string) (*surveyAnswers, error) {
-	a := &surveyAnswers{}
+func newSurveyAnswers(options *NewOptions, previous *history.Answers) (*surveyAnswers, error) {
+	a := &surveyAnswers{
+		Product:  previous.Product,
+		Language: previous.Language,
+		Type:     previous.Type,
+		Features: previous.Features,
+		history:  previous,
+	}
+	if options.Type != "" {
+		a.Type = options.Type
+	}
+	if len(options.Features) > 0 {
+		a.Features = options.Features
+	}
 	if err := defaults.Set(a); err != nil {
 		// Without default values
-		return loadProtoValues(protoFilename, a)
+		return loadProtoValues(options.ProtoFilename, a)
 	}
 
-	return loadProtoValues(protoFilename, a)
+	return loadProtoValues(options.ProtoFilename, a)
 }
 
 func loadProtoValues(protoFilename string, a *surveyAnswers) (*surveyAnswers, error) {
@@ -47,6 +76,34 @@ func loadProtoValues(protoFilename string, a *surveyAnswers) (*surveyAnswers, er
 	return a, nil
 }
 
+// SplitName splits the service name, which can be a fully qualified name
+// (like github.com/acme/orders), into the service name and its Go module
+// path. Bare names use vcsPath, when set, as their module path prefix.
+func (s *surveyAnswers) SplitName(vcsPath string) error {
+	var (
+		name       = strings.Trim(s.Name, "/")
+		modulePath = name
+	)
+
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	} else {
+		modulePath = strcase.ToKebab(name)
+		if vcsPath != "" {
+			modulePath = path.Join(vcsPath, modulePath)
+		}
+	}
+
+	if err := module.CheckImportPath(modulePath); err != nil {
+		return fmt.Errorf("invalid service name '%s': %w", s.Name, err)
+	}
+
+	s.Name = name
+	s.Module = modulePath
+
+	return nil
+}
+
 func (s *surveyAnswers) TemplateNames() []template.File {
 	names := []template.File{
 		{
@@ -83,6 +140,42 @@ func (s *surveyAnswers) AddFeatureDefinitions(name string, answers interface{})
 	}
 }
 
+func (s *surveyAnswers) SetFeatureAnswers(uiName, name string, answers map[string]interface{}) {
+	if s.featureAnswers == nil {
+		s.featureAnswers = make(map[string]*featureSurveyAnswers)
+	}
+
+	s.featureAnswers[uiName] = &featureSurveyAnswers{
+		name:    name,
+		answers: answers,
+	}
+}
+
+func (s *surveyAnswers) FeatureAnswers(uiName string) (map[string]interface{}, bool) {
+	f, ok := s.featureAnswers[uiName]
+	if !ok {
+		return nil, false
+	}
+
+	return f.answers, true
+}
+
+func (s *surveyAnswers) RemoveFeature(uiName string) {
+	f, ok := s.featureAnswers[uiName]
+	if !ok {
+		return
+	}
+
+	delete(s.featureDefinitions, f.name)
+	delete(s.featureAnswers, uiName)
+}
+
+func (s *surveyAnswers) ResetServiceType() {
+	s.HTTPType = ""
+	s.serviceAnswers = nil
+	s.serviceDefinitions = nil
+}
+
 func (s *surveyAnswers) SetServiceDefinitions(answers interface{}) {
 	s.serviceDefinitions = &surveyAnswersDefinitions{
 		definitions: answers,
@@ -105,6 +198,58 @@ func (s *surveyAnswers) ServiceAnswers() map[string]interface{} {
 	return s.serviceAnswers
 }
 
+func (s *surveyAnswers) PluginDefaults(name string, current map[string]interface{}) map[string]interface{} {
+	if len(current) != 0 {
+		return current
+	}
+
+	return s.history.PluginAnswers(name)
+}
+
+func (s *surveyAnswers) UpdateHistory() {
+	s.history.Product = s.Product
+	s.history.Language = s.Language
+	s.history.Type = s.Type
+	s.history.Features = s.Features
+
+	if len(s.serviceAnswers) != 0 {
+		s.history.SetPluginAnswers(s.Type, s.serviceAnswers)
+	}
+	for uiName, f := range s.featureAnswers {
+		s.history.SetPluginAnswers(uiName, f.answers)
+	}
+}
+
+// ManifestAnswers returns the answers recorded in the generation manifest,
+// which are enough to generate the service again.
+func (s *surveyAnswers) ManifestAnswers() map[string]interface{} {
+	answers := map[string]interface{}{
+		"name":      s.Name,
+		"module":    s.Module,
+		"type":      s.Type,
+		"language":  s.Language,
+		"version":   s.Version,
+		"product":   s.Product,
+		"features":  s.Features,
+		"lifecycle": s.Lifecycle,
+	}
+	if s.HTTPType != "" {
+		answers["http_type"] = s.HTTPType
+	}
+	if len(s.serviceAnswers) != 0 {
+		answers["service_answers"] = s.serviceAnswers
+	}
+	if len(s.featureAnswers) != 0 {
+		features := make(map[string]interface{}, len(s.featureAnswers))
+		for uiName, f := range s.featureAnswers {
+			features[uiName] = f.answers
+		}
+		answers["feature_answers"] = features
+	}
+
+	return answers
+}
+
 func (s *surveyAnswers) ServiceType() string {
 	svcType := s.Type
 	if svcType == definition.ServiceTypeHTTP.String() {
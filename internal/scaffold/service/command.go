@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -14,6 +15,9 @@ import (
 	"github.com/mikros-dev/mikros-cli/internal/definitions"
 	"github.com/mikros-dev/mikros-cli/internal/fs"
 	"github.com/mikros-dev/mikros-cli/internal/golang"
+	"github.com/mikros-dev/mikros-cli/internal/history"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+	"github.com/mikros-dev/mikros-cli/internal/plugin"
 	"github.com/mikros-dev/mikros-cli/internal/plugin/client"
 	"github.com/mikros-dev/mikros-cli/internal/protobuf"
 	"github.com/mikros-dev/mikros-cli/internal/settings"
@@ -28,62 +32,130 @@ type NewOptions struct {
 	// ProtoFilename defines the location of the protobuf file used for the
 	// service.
 	ProtoFilename string
+
+	// Profile is the name of the profile whose answers history is used as
+	// default values and whose templates directory is used.
+	Profile string
+
+	// Type optionally sets the initial service type.
+	Type string
+
+	// Features optionally sets the initial features of the service.
+	Features []string
 }
 
-// New creates a new service template directory with initial source files.
-func New(cfg *settings.Settings, options *NewOptions) error {
+// New creates a new service template directory with initial source files. It
+// returns the path of the created directory.
+func New(cfg *settings.Settings, options *NewOptions) (string, error) {
+	hist, err := history.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load answers history: %w", err)
+	}
+
 	// Execute the base survey
-	answers, err := runSurvey(cfg, options.ProtoFilename)
+	answers, err := runSurvey(cfg, options, hist.Get(options.Profile))
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Then execute everything specific for the selected service type.
 	svc, err := runServiceTypeSurvey(cfg, answers)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Presents only questions from selected features
 	for _, name := range answers.Features {
-		featureName, defs, err := runFeatureSurvey(cfg, name)
-		if err != nil {
-			return err
-		}
-		if d, ok := defs.(map[string]interface{}); ok && len(d) != 0 {
-			answers.AddFeatureDefinitions(featureName, defs)
+		if err := runFeatureSurvey(cfg, answers, name); err != nil {
+			return "", err
 		}
 	}
 
-	return generateTemplates(options, answers, svc)
+	// Lets the user review (and fix) everything before generating anything.
+	svc, err = runReview(cfg, answers, svc)
+	if err != nil {
+		return "", err
+	}
+
+	if err := answers.SplitName(cfg.GetProfile(options.Profile).Project.Service.VcsPath); err != nil {
+		return "", err
+	}
+
+	searchPaths := template.NewSearchPaths(cfg.GetProfile(options.Profile).TemplatesDir)
+
+	m, err := newManifest(cfg, answers, svc)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := generateTemplates(options, answers, svc, searchPaths, m)
+	if err != nil {
+		return "", err
+	}
+
+	setProtoFile(m, path, options.ProtoFilename)
+	if err := m.Write(path); err != nil {
+		return "", fmt.Errorf("failed to write generation manifest: %w", err)
+	}
+
+	// Remember answers to use them as default values next time
+	answers.UpdateHistory()
+	if err := hist.Write(); err != nil {
+		return "", fmt.Errorf("failed to write answers history: %w", err)
+	}
+
+	return path, nil
 }
 
-func generateTemplates(options *NewOptions, answers *surveyAnswers, svc *client.Service) error {
+func generateTemplates(
+	options *NewOptions,
+	answers *surveyAnswers,
+	svc *client.Service,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) (string, error) {
 	var destinationPath = filepath.Join(options.Path, strings.ToLower(answers.Name))
 
+	// The protobuf file is only read after switching to the destination
+	// path, so it can't be relative to the current one.
+	if options.ProtoFilename != "" {
+		filename, err := filepath.Abs(options.ProtoFilename)
+		if err != nil {
+			return "", err
+		}
+
+		o := *options
+		o.ProtoFilename = filename
+		options = &o
+	}
+
 	// Set the project base path
 	if destinationPath == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+			return "", fmt.Errorf("failed to get current working directory: %w", err)
 		}
 
 		destinationPath = filepath.Join(cwd, strings.ToLower(answers.Name))
 	}
 
-	if _, err := fs.CreatePath(destinationPath); err != nil {
-		return fmt.Errorf("failed to create service directory: %w", err)
+	destinationPath, err := fs.CreatePath(destinationPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create service directory: %w", err)
 	}
 
 	// Creates the service.toml file
 	if err := writeServiceDefinitions(destinationPath, answers); err != nil {
-		return err
+		return "", err
+	}
+	if err := addServiceDefinitions(m, destinationPath); err != nil {
+		return "", err
 	}
 
 	// Switch to the destination path to create template sources
 	cwd, err := fs.ChangeDir(destinationPath)
 	if err != nil {
-		return fmt.Errorf("failed to change directory: %w", err)
+		return "", fmt.Errorf("failed to change directory: %w", err)
 	}
 
 	defer func() {
@@ -91,12 +163,16 @@ func generateTemplates(options *NewOptions, answers *surveyAnswers, svc *client.
 	}()
 
 	// creates go.mod
-	if err := golang.ModInit(strcase.ToKebab(answers.Name)); err != nil {
-		return fmt.Errorf("failed to create go.mod: %w", err)
+	if err := golang.ModInit(answers.Module); err != nil {
+		return "", fmt.Errorf("failed to create go.mod: %w", err)
 	}
 
 	// creates go source templates
-	return generateSources(options, answers, svc)
+	if err := generateSources(options, answers, svc, searchPaths, m); err != nil {
+		return "", err
+	}
+
+	return destinationPath, nil
 }
 
 func writeServiceDefinitions(path string, answers *surveyAnswers) error {
@@ -129,7 +205,13 @@ func writeServiceDefinitions(path string, answers *surveyAnswers) error {
 	return nil
 }
 
-func generateSources(options *NewOptions, answers *surveyAnswers, svc *client.Service) error {
+func generateSources(
+	options *NewOptions,
+	answers *surveyAnswers,
+	svc *client.Service,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
+) error {
 	var externalTemplate *mtemplate.Template
 	if svc != nil {
 		res, err := svc.GetTemplates(answers.ServiceAnswers())
@@ -144,7 +226,7 @@ func generateSources(options *NewOptions, answers *surveyAnswers, svc *client.Se
 		return err
 	}
 
-	return createServiceTemplates(answers.TemplateNames(), tplCtx, externalTemplate)
+	return createServiceTemplates(answers.TemplateNames(), tplCtx, externalTemplate, searchPaths, m)
 }
 
 func generateTemplateContext(
@@ -174,7 +256,24 @@ func generateTemplateContext(
 		return true
 	}
 
-	newServiceArgs, err := generateNewServiceArgs(answers, externalTemplate)
+	var (
+		pbFile   *protobuf.Proto
+		registry *protobuf.Registry
+	)
+	if filename := options.ProtoFilename; filename != "" {
+		// The proto root is unknown, so imports are searched inside all
+		// directories containing the file.
+		registry = protobuf.NewRegistry(protobuf.ImportPaths(filename)...)
+
+		f, err := registry.Load(filename)
+		if err != nil {
+			return TemplateContext{}, err
+		}
+		pbFile = f
+	}
+	pbService, _ := protoService(answers, pbFile)
+
+	newServiceArgs, err := generateNewServiceArgs(answers, externalTemplate, pbService)
 	if err != nil {
 		return TemplateContext{}, err
 	}
@@ -187,7 +286,8 @@ func generateTemplateContext(
 		serviceType:              answers.ServiceType(),
 		NewServiceArgs:           newServiceArgs,
 		ServiceName:              answers.Name,
-		Imports:                  generateImports(answers),
+		ModulePath:               answers.Module,
+		Imports:                  generateImports(answers, pbFile),
 		ServiceTypeCustomAnswers: defs,
 	}
 
@@ -196,28 +296,37 @@ func generateTemplateContext(
 		tplCtx.ExternalFeaturesArg = externalTemplate.WithExternalFeaturesArg
 	}
 
-	if filename := options.ProtoFilename; filename != "" {
-		pbFile, err := protobuf.Parse(filename)
-		if err != nil {
-			return TemplateContext{}, err
-		}
-		tplCtx.GrpcMethods = pbFile.Methods
+	if pbFile != nil {
+		tplCtx.GrpcMethods = pbFile.Methods()
+	}
+	if pb, ok := protobufImport(answers, pbFile); ok && pbService != nil {
+		handlers, imports := generateHandlers(pbService, pbFile, registry, pb)
+		tplCtx.Handlers = handlers
+		tplCtx.Imports["service"] = append(tplCtx.Imports["service"], imports...)
 	}
 
 	return tplCtx, nil
 }
 
-func generateNewServiceArgs(answers *surveyAnswers, externalTemplate *mtemplate.Template) (string, error) {
+func generateNewServiceArgs(
+	answers *surveyAnswers,
+	externalTemplate *mtemplate.Template,
+	pbService *protobuf.Service,
+) (string, error) {
 	var (
 		svcSnake     = strcase.ToSnake(answers.Name)
+		pbName       = strcase.ToCamel(answers.Name) + "Service"
 		svcInitBlock string
 	)
+	if pbService != nil {
+		pbName = protobuf.GoCamelCase(pbService.Name)
+	}
 
 	switch answers.ServiceType() {
 	case definition.ServiceTypeGRPC.String():
 		svcInitBlock = fmt.Sprintf(`"grpc": &options.GrpcServiceOptions{
-				ProtoServiceDescription: &%spb.%sService_ServiceDesc,
-			},`, svcSnake, strcase.ToCamel(answers.Name))
+				ProtoServiceDescription: &%spb.%s_ServiceDesc,
+			},`, svcSnake, pbName)
 
 	case definition.ServiceTypeHTTPSpec.String():
 		svcInitBlock = fmt.Sprintf(`"http-spec": &options.HTTPSpecServiceOptions{
@@ -282,7 +391,7 @@ func externalTemplateInitBlock(answers *surveyAnswers, externalTemplate *mtempla
 	return initBlock, nil
 }
 
-func generateImports(answers *surveyAnswers) map[string][]ImportContext {
+func generateImports(answers *surveyAnswers, pbFile *protobuf.Proto) map[string][]ImportContext {
 	imports := map[string][]ImportContext{
 		"main": {
 			{
@@ -304,6 +413,11 @@ func generateImports(answers *surveyAnswers) map[string][]ImportContext {
 		},
 	}
 
+	if pb, ok := protobufImport(answers, pbFile); ok {
+		imports["main"] = append(imports["main"], pb)
+		imports["service"] = append(imports["service"], pb)
+	}
+
 	if len(answers.Lifecycle) > 0 {
 		imports["lifecycle"] = append(imports["lifecycle"], ImportContext{
 			Path: "context",
@@ -324,21 +438,49 @@ func generateImports(answers *surveyAnswers) map[string][]ImportContext {
 	return imports
 }
 
+// protobufImport returns the import of the Go package generated from the
+// service protobuf file, used by gRPC and HTTP (http-spec) services. When the
+// file does not have the go_package option, the package is expected inside
+// the service module.
+func protobufImport(answers *surveyAnswers, pbFile *protobuf.Proto) (ImportContext, bool) {
+	switch answers.ServiceType() {
+	case definition.ServiceTypeGRPC.String(), definition.ServiceTypeHTTPSpec.String():
+	default:
+		return ImportContext{}, false
+	}
+
+	var (
+		alias      = strcase.ToSnake(answers.Name) + "pb"
+		importPath = path.Join(answers.Module, alias)
+	)
+	if pbFile != nil && pbFile.GoPackage != "" {
+		importPath = pbFile.GoPackage
+	}
+
+	return ImportContext{
+		Alias: alias,
+		Path:  importPath,
+	}, true
+}
+
 func createServiceTemplates(
 	filenames []template.File,
 	tplContext TemplateContext,
 	externalTemplate *mtemplate.Template,
+	searchPaths template.SearchPaths,
+	m *manifest.Manifest,
 ) error {
 	// Execute our templates
 	session, err := template.NewSessionFromFiles(&template.LoadOptions{
 		TemplatesToUse: filenames,
-		FilesBasePath:  "assets",
-	}, templateFiles)
+		FilesBasePath:  serviceTemplates.BasePath,
+		SearchPaths:    searchPaths.Set(serviceTemplates.Name),
+	}, serviceTemplates.Files)
 	if err != nil {
 		return err
 	}
 
-	if err := runTemplates(session, tplContext); err != nil {
+	if err := runTemplates(session, tplContext, m); err != nil {
 		return err
 	}
 
@@ -371,12 +513,13 @@ func createServiceTemplates(
 
 		session, err := template.NewSessionFromData(&template.LoadOptions{
 			TemplatesToUse: templateNames,
+			Origin:         "plugin:" + tplContext.serviceType,
 		}, files)
 		if err != nil {
 			return err
 		}
 
-		if err := runTemplates(session, nil); err != nil {
+		if err := runTemplates(session, nil, m); err != nil {
 			return err
 		}
 	}
@@ -384,25 +527,71 @@ func createServiceTemplates(
 	return nil
 }
 
-func runTemplates(session *template.Session, context interface{}) error {
+func runTemplates(session *template.Session, context interface{}, m *manifest.Manifest) error {
 	generated, err := session.ExecuteTemplates(context)
 	if err != nil {
 		return err
 	}
 
 	for _, gen := range generated {
-		file, err := os.Create(gen.Filename())
-		if err != nil {
+		if err := gen.Write(""); err != nil {
 			return err
 		}
+		m.AddGenerated(gen.Filename(), gen)
+	}
 
-		if _, err := file.Write(gen.Content()); err != nil {
-			_ = file.Close()
-			return err
+	return nil
+}
+
+// newManifest creates the generation manifest of the service with the
+// answers and plugins used to generate it.
+func newManifest(cfg *settings.Settings, answers *surveyAnswers, svc *client.Service) (*manifest.Manifest, error) {
+	m := manifest.New("service-template")
+	m.SetAnswers(answers.ManifestAnswers())
+
+	if svc != nil {
+		m.AddPlugin(pluginInfo(answers.Type, "service", svc))
+	}
+
+	for _, uiName := range answers.Features {
+		f, err := plugin.GetFeaturePlugin(cfg, uiName)
+		if err != nil {
+			return nil, err
 		}
+		if f != nil {
+			m.AddPlugin(pluginInfo(uiName, "feature", f))
+		}
+	}
+
+	return m, nil
+}
+
+type versionedPlugin interface {
+	GetVersion() (string, error)
+	Checksum() (string, error)
+}
 
-		_ = file.Close()
+func pluginInfo(name, kind string, p versionedPlugin) *manifest.Plugin {
+	// Plugins that can't tell their versions are still recorded, since
+	// their checksums identify them.
+	version, _ := p.GetVersion()
+	checksum, _ := p.Checksum()
+
+	return &manifest.Plugin{
+		Name:     name,
+		Kind:     kind,
+		Version:  version,
+		Checksum: checksum,
+	}
+}
+
+func addServiceDefinitions(m *manifest.Manifest, path string) error {
+	filename := filepath.Join(path, "service.toml")
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read service definitions file: %w", err)
 	}
+	m.AddFile(filename, manifest.OriginDefinitions, data)
 
 	return nil
 }
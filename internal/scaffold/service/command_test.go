@@ -0,0 +1,226 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/golden"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+	"github.com/mikros-dev/mikros-cli/internal/plugin"
+	"github.com/mikros-dev/mikros-cli/internal/plugin/client"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+)
+
+func TestGenerateTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers *surveyAnswers
+		vcsPath string
+		proto   string
+		build   bool
+	}{
+		{
+			name: "worker",
+			answers: &surveyAnswers{
+				Name:      "billing",
+				Type:      "worker",
+				Lifecycle: []string{"OnStart", "OnFinish"},
+			},
+			build: true,
+		},
+		{
+			name: "http",
+			answers: &surveyAnswers{
+				Name:     "billing",
+				Type:     "http",
+				HTTPType: "http",
+			},
+			build: true,
+		},
+		{
+			name: "script",
+			answers: &surveyAnswers{
+				Name: "billing",
+				Type: "script",
+			},
+			build: true,
+		},
+		{
+			name: "qualified",
+			answers: &surveyAnswers{
+				Name: "github.com/acme/billing-worker",
+				Type: "worker",
+			},
+			vcsPath: "github.com/ignored",
+			build:   true,
+		},
+		{
+			name: "prefixed",
+			answers: &surveyAnswers{
+				Name: "billing_worker",
+				Type: "worker",
+			},
+			vcsPath: "github.com/acme/services",
+			build:   true,
+		},
+		{
+			name: "grpc",
+			answers: &surveyAnswers{
+				Name:      "billing",
+				Type:      "grpc",
+				Lifecycle: []string{"OnStart"},
+			},
+			proto: "billing_api.proto",
+		},
+		{
+			name: "grpc-streaming",
+			answers: &surveyAnswers{
+				Name: "orders",
+				Type: "grpc",
+			},
+			proto: "acme/orders/orders_api.proto",
+		},
+		{
+			name: "http-spec",
+			answers: &surveyAnswers{
+				Name:     "billing",
+				Type:     "http",
+				HTTPType: "http-spec",
+			},
+			proto: "billing_api.proto",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.answers.Language = "go"
+			tt.answers.Version = "v0.1.0"
+			tt.answers.Product = "acme"
+
+			cfg := &settings.Settings{}
+			cfg.App.Project.Service.VcsPath = tt.vcsPath
+
+			path := generateService(t, cfg, tt.answers, nil, tt.proto)
+			golden.Compare(t, path, filepath.Join("testdata", "golden", tt.name))
+
+			if tt.build {
+				t.Run("build", func(t *testing.T) {
+					golden.BuildModule(t, path)
+				})
+			}
+		})
+	}
+}
+
+func TestGenerateTemplatesWithPlugin(t *testing.T) {
+	cfg := &settings.Settings{}
+	cfg.Paths.Plugins.Services = t.TempDir()
+
+	// The plugin example is built to prove it still builds with the SDK.
+	golden.BuildPlugin(t, filepath.Join("..", "..", "..", "examples", "services", "consumer"),
+		filepath.Join(cfg.Paths.Plugins.Services, "consumer"))
+
+	svc, err := plugin.GetServicePlugin(cfg, "consumer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc == nil {
+		t.Fatal("consumer plugin not found")
+	}
+
+	answers := &surveyAnswers{
+		Name:     "notifier",
+		Type:     "consumer",
+		Language: "go",
+		Version:  "v0.1.0",
+		Product:  "acme",
+	}
+	serviceAnswers := map[string]interface{}{
+		"consumer": []interface{}{
+			map[string]interface{}{
+				"topic_name":         "user_created",
+				"topic_service_name": "user",
+			},
+			map[string]interface{}{
+				"topic_name":         "user_deleted",
+				"topic_service_name": "user",
+			},
+		},
+	}
+
+	defs, err := svc.ValidateAnswers(serviceAnswers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	answers.SetServiceAnswers(serviceAnswers)
+	answers.SetServiceDefinitions(defs)
+
+	path := generateService(t, cfg, answers, svc, "")
+	golden.Compare(t, path, filepath.Join("testdata", "golden", "consumer"))
+}
+
+// generateService generates a service the same way the new command does,
+// without asking anything, and returns its path.
+func generateService(
+	t *testing.T,
+	cfg *settings.Settings,
+	answers *surveyAnswers,
+	svc *client.Service,
+	proto string,
+) string {
+	t.Helper()
+
+	var (
+		basePath      = t.TempDir()
+		protoFilename string
+	)
+
+	if proto != "" {
+		// The protobuf files are copied, since the proto file is recorded
+		// relative to the service and must always be at the same place.
+		protosPath := filepath.Join(basePath, "protos")
+		if err := os.CopyFS(protosPath, os.DirFS(filepath.Join("testdata", "protos"))); err != nil {
+			t.Fatal(err)
+		}
+		protoFilename = filepath.Join(protosPath, filepath.FromSlash(proto))
+	}
+
+	if err := answers.SplitName(cfg.GetProfile("default").Project.Service.VcsPath); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := newManifest(cfg, answers, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := generateTemplates(&NewOptions{
+		Path:          filepath.Join(basePath, "services"),
+		ProtoFilename: protoFilename,
+	}, answers, svc, nil, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setProtoFile(m, path, protoFilename)
+	if err := m.Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The manifest must be enough to generate the same service again.
+	loaded, err := manifest.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regenerated, err := Regenerate(cfg, loaded, &RegenerateOptions{
+		ProjectPath: path,
+		Path:        t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.CompareDirs(t, regenerated, path)
+
+	return path
+}
@@ -2,7 +2,20 @@ package service
 
 import (
 	"embed"
+
+	"github.com/mikros-dev/mikros-cli/internal/template"
 )
 
 //go:embed assets/*.tmpl
 var templateFiles embed.FS
+
+var serviceTemplates = template.Set{
+	Name:     "service",
+	Files:    templateFiles,
+	BasePath: "assets",
+}
+
+// TemplateSets returns the built-in template sets used to create services.
+func TemplateSets() []template.Set {
+	return []template.Set{serviceTemplates}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+const (
+	// HandlersFile is the file, relative to the service directory, where
+	// handlers generated from the service protobuf API are.
+	HandlersFile = "service.go"
+
+	// fieldBehaviorOption is the option marking fields that must be set by
+	// clients.
+	fieldBehaviorOption = "(google.api.field_behavior)"
+)
+
+var pathVariable = regexp.MustCompile(`{([a-zA-Z_][a-zA-Z0-9_]*)(=[^}]*)?}`)
+
+// Handler is a service handler generated from a method of the service
+// protobuf API.
+type Handler struct {
+	Name    string
+	Comment []string
+	Mode    protobuf.StreamingMode
+
+	// RequestType, ResponseType and StreamType are the Go types used by the
+	// handler.
+	RequestType  string
+	ResponseType string
+	StreamType   string
+
+	// RequiredFields are the request fields the handler must validate.
+	RequiredFields []*RequiredField
+}
+
+// RequiredField is a request field that must be set.
+type RequiredField struct {
+	Name      string
+	Getter    string
+	ZeroValue string
+}
+
+// IsUnary returns true if the handler receives a single request and returns
+// a single response.
+func (h *Handler) IsUnary() bool {
+	return h.Mode == protobuf.StreamingModeUnary
+}
+
+// IsServerStreaming returns true if the handler receives a single request and
+// sends a stream of responses.
+func (h *Handler) IsServerStreaming() bool {
+	return h.Mode == protobuf.StreamingModeServer
+}
+
+// IsClientStreaming returns true if the handler receives a stream of requests
+// and returns a single response.
+func (h *Handler) IsClientStreaming() bool {
+	return h.Mode == protobuf.StreamingModeClient
+}
+
+// IsBidiStreaming returns true if the handler receives and sends streams.
+func (h *Handler) IsBidiStreaming() bool {
+	return h.Mode == protobuf.StreamingModeBidi
+}
+
+// handlerGenerator builds handlers of a protobuf service, collecting the
+// imports of the Go packages their types belong to.
+type handlerGenerator struct {
+	pbFile   *protobuf.Proto
+	registry *protobuf.Registry
+	pbImport ImportContext
+	imports  []ImportContext
+}
+
+// protoService returns the protobuf service implemented by the service: the
+// one named after it or, otherwise, the first one.
+func protoService(answers *surveyAnswers, pbFile *protobuf.Proto) (*protobuf.Service, bool) {
+	if pbFile == nil || len(pbFile.Services) == 0 {
+		return nil, false
+	}
+
+	if svc, ok := pbFile.Service(strcase.ToCamel(answers.Name) + "Service"); ok {
+		return svc, true
+	}
+
+	return pbFile.Services[0], true
+}
+
+func generateHandlers(
+	svc *protobuf.Service,
+	pbFile *protobuf.Proto,
+	registry *protobuf.Registry,
+	pbImport ImportContext,
+) ([]*Handler, []ImportContext) {
+	g := &handlerGenerator{
+		pbFile:   pbFile,
+		registry: registry,
+		pbImport: pbImport,
+	}
+
+	handlers := make([]*Handler, len(svc.Methods))
+	for i, method := range svc.Methods {
+		handlers[i] = g.handler(svc, method)
+	}
+
+	return handlers, g.imports
+}
+
+func (g *handlerGenerator) handler(svc *protobuf.Service, method *protobuf.Method) *Handler {
+	h := &Handler{
+		Name:         method.Name,
+		Mode:         method.StreamingMode(),
+		RequestType:  g.goType(method.InputType, method.InputName),
+		ResponseType: g.goType(method.OutputType, method.OutputName),
+	}
+
+	if method.Comment != "" {
+		h.Comment = strings.Split(method.Comment, "\n")
+	}
+	if method.IsStreaming() {
+		h.StreamType = fmt.Sprintf("%s.%s_%sServer", g.pbImport.Alias, protobuf.GoCamelCase(svc.Name), protobuf.GoCamelCase(method.Name))
+	}
+	if !method.ClientStreaming {
+		h.RequiredFields = g.requiredFields(method)
+	}
+
+	return h
+}
+
+// goType returns the Go type of a message, adding the import of its package
+// when it belongs to another one.
+func (g *handlerGenerator) goType(fullName, name string) string {
+	if fullName == "" {
+		// Types of imports not found, like well-known types.
+		fullName = strings.TrimPrefix(name, ".")
+	}
+
+	t, ok := g.registry.GoType(fullName)
+	if !ok {
+		return g.pbImport.Alias + "." + protobuf.GoCamelCase(name)
+	}
+	if t.ImportPath == "" || t.ImportPath == g.pbFile.GoPackage {
+		return g.pbImport.Alias + "." + t.Name
+	}
+
+	return g.importAlias(t) + "." + t.Name
+}
+
+func (g *handlerGenerator) importAlias(t *protobuf.GoType) string {
+	for _, imp := range g.imports {
+		if imp.Path == t.ImportPath {
+			return imp.Alias
+		}
+	}
+
+	alias := t.PackageName
+	for i := 2; g.aliasInUse(alias); i++ {
+		alias = fmt.Sprintf("%s%d", t.PackageName, i)
+	}
+
+	g.imports = append(g.imports, ImportContext{
+		Alias: alias,
+		Path:  t.ImportPath,
+	})
+
+	return alias
+}
+
+func (g *handlerGenerator) aliasInUse(alias string) bool {
+	if alias == g.pbImport.Alias {
+		return true
+	}
+	for _, imp := range g.imports {
+		if imp.Alias == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requiredFields returns request fields with an obvious validation: the ones
+// bound to HTTP path variables and the ones marked as required with the
+// google.api.field_behavior option.
+func (g *handlerGenerator) requiredFields(method *protobuf.Method) []*RequiredField {
+	request, ok := g.registry.Message(method.InputType)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	if method.HTTP != nil {
+		for _, match := range pathVariable.FindAllStringSubmatch(method.HTTP.Path, -1) {
+			names = append(names, match[1])
+		}
+	}
+	for _, f := range request.Fields {
+		if isRequiredField(f) {
+			names = append(names, f.Name)
+		}
+	}
+
+	var (
+		fields []*RequiredField
+		seen   = make(map[string]bool)
+	)
+	for _, name := range names {
+		f, ok := request.Field(name)
+		if !ok || seen[name] {
+			continue
+		}
+
+		zero, ok := zeroValue(f)
+		if !ok {
+			continue
+		}
+
+		seen[name] = true
+		fields = append(fields, &RequiredField{
+			Name:      f.Name,
+			Getter:    "Get" + protobuf.GoCamelCase(f.Name),
+			ZeroValue: zero,
+		})
+	}
+
+	return fields
+}
+
+func isRequiredField(f *protobuf.Field) bool {
+	option, ok := f.Options.Get(fieldBehaviorOption)
+	if !ok {
+		return false
+	}
+
+	if option.Value.Scalar == "REQUIRED" {
+		return true
+	}
+	for _, v := range option.Value.List {
+		if v.Scalar == "REQUIRED" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// zeroValue returns the Go zero value of fields that can be compared with
+// it.
+func zeroValue(f *protobuf.Field) (string, bool) {
+	if f.Repeated || f.IsMap() || f.OneOf != "" {
+		return "", false
+	}
+
+	switch f.TypeKind {
+	case protobuf.TypeKindScalar:
+		switch f.Type {
+		case "string":
+			return `""`, true
+		case "bool", "bytes":
+			return "", false
+		}
+
+		return "0", true
+
+	case protobuf.TypeKindMessage:
+		return "nil", true
+	}
+
+	return "", false
+}
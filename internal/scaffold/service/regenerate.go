@@ -0,0 +1,207 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+	"github.com/mikros-dev/mikros-cli/internal/plugin"
+	"github.com/mikros-dev/mikros-cli/internal/plugin/client"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/template"
+)
+
+// RegenerateOptions holds options for generating a service again.
+type RegenerateOptions struct {
+	// ProjectPath is the directory of the service being regenerated.
+	ProjectPath string
+
+	// Path is where the service is generated again.
+	Path string
+
+	// SearchPaths holds the templates search paths. They must be created
+	// inside the service directory, so its custom templates are used.
+	SearchPaths template.SearchPaths
+}
+
+// recordedAnswers are the answers recorded in the generation manifest by
+// ManifestAnswers.
+type recordedAnswers struct {
+	Name           string                            `json:"name"`
+	Module         string                            `json:"module"`
+	Type           string                            `json:"type"`
+	Language       string                            `json:"language"`
+	Version        string                            `json:"version"`
+	Product        string                            `json:"product"`
+	Features       []string                          `json:"features"`
+	Lifecycle      []string                          `json:"lifecycle"`
+	HTTPType       string                            `json:"http_type"`
+	ProtoFile      string                            `json:"proto_file"`
+	ServiceAnswers map[string]interface{}            `json:"service_answers"`
+	FeatureAnswers map[string]map[string]interface{} `json:"feature_answers"`
+}
+
+// Regenerate generates a service again, without asking anything, using the
+// answers recorded in its generation manifest and the current templates and
+// plugins. It returns the path of the generated service.
+func Regenerate(cfg *settings.Settings, m *manifest.Manifest, options *RegenerateOptions) (string, error) {
+	if err := m.CheckAnswers(); err != nil {
+		return "", err
+	}
+
+	recorded, err := decodeRecordedAnswers(m.Answers)
+	if err != nil {
+		return "", err
+	}
+
+	answers := &surveyAnswers{
+		Name:      recorded.Name,
+		Module:    recorded.Module,
+		Type:      recorded.Type,
+		Language:  recorded.Language,
+		Version:   recorded.Version,
+		Product:   recorded.Product,
+		Features:  recorded.Features,
+		Lifecycle: recorded.Lifecycle,
+		HTTPType:  recorded.HTTPType,
+	}
+
+	if answers.Module == "" {
+		// Services generated before module paths were recorded used their
+		// names as module paths.
+		if err := answers.SplitName(""); err != nil {
+			return "", err
+		}
+	}
+
+	svc, err := validateServiceAnswers(cfg, answers, recorded.ServiceAnswers)
+	if err != nil {
+		return "", err
+	}
+
+	for _, uiName := range answers.Features {
+		if err := validateFeatureAnswers(cfg, answers, uiName, recorded.FeatureAnswers[uiName]); err != nil {
+			return "", err
+		}
+	}
+
+	protoFilename := recorded.ProtoFile
+	if protoFilename != "" && !filepath.IsAbs(protoFilename) {
+		protoFilename = filepath.Join(options.ProjectPath, protoFilename)
+	}
+
+	regenerated, err := newManifest(cfg, answers, svc)
+	if err != nil {
+		return "", err
+	}
+	setProtoFile(regenerated, options.ProjectPath, protoFilename)
+
+	path, err := generateTemplates(&NewOptions{
+		Path:          options.Path,
+		ProtoFilename: protoFilename,
+	}, answers, svc, options.SearchPaths, regenerated)
+	if err != nil {
+		return "", err
+	}
+
+	if err := regenerated.Write(path); err != nil {
+		return "", fmt.Errorf("failed to write generation manifest: %w", err)
+	}
+
+	return path, nil
+}
+
+func decodeRecordedAnswers(answers map[string]interface{}) (*recordedAnswers, error) {
+	b, err := json.Marshal(answers)
+	if err != nil {
+		return nil, err
+	}
+
+	var recorded recordedAnswers
+	if err := json.Unmarshal(b, &recorded); err != nil {
+		return nil, fmt.Errorf("invalid manifest answers: %w", err)
+	}
+	if recorded.Name == "" || recorded.Type == "" {
+		return nil, fmt.Errorf("manifest does not have the service name and type")
+	}
+
+	return &recorded, nil
+}
+
+func validateServiceAnswers(
+	cfg *settings.Settings,
+	answers *surveyAnswers,
+	serviceAnswers map[string]interface{},
+) (*client.Service, error) {
+	svc, err := plugin.GetServicePlugin(cfg, answers.Type)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
+		return nil, nil
+	}
+
+	defs, err := svc.ValidateAnswers(serviceAnswers)
+	if err != nil {
+		return nil, err
+	}
+
+	answers.SetServiceAnswers(serviceAnswers)
+	answers.SetServiceDefinitions(defs)
+
+	return svc, nil
+}
+
+func validateFeatureAnswers(
+	cfg *settings.Settings,
+	answers *surveyAnswers,
+	uiName string,
+	featureAnswers map[string]interface{},
+) error {
+	f, err := plugin.GetFeaturePlugin(cfg, uiName)
+	if err != nil {
+		return err
+	}
+	if f == nil || featureAnswers == nil {
+		// Features without surveys don't have answers.
+		return nil
+	}
+
+	defs, err := f.ValidateAnswers(featureAnswers)
+	if err != nil {
+		return err
+	}
+
+	featureName, err := f.GetName()
+	if err != nil {
+		return err
+	}
+
+	answers.SetFeatureAnswers(uiName, featureName, featureAnswers)
+	if len(defs) != 0 {
+		answers.AddFeatureDefinitions(featureName, defs)
+	}
+
+	return nil
+}
+
+// setProtoFile records the protobuf file used by the service, relative to
+// the service directory, so it can be found when regenerating it.
+func setProtoFile(m *manifest.Manifest, projectPath, protoFilename string) {
+	if protoFilename == "" {
+		return
+	}
+
+	if abs, err := filepath.Abs(protoFilename); err == nil {
+		protoFilename = abs
+	}
+	if rel, err := filepath.Rel(projectPath, protoFilename); err == nil {
+		protoFilename = filepath.ToSlash(rel)
+	}
+
+	if m.Answers == nil {
+		m.Answers = make(map[string]interface{})
+	}
+	m.Answers["proto_file"] = protoFilename
+}
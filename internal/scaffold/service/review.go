@@ -0,0 +1,206 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/mikros-dev/mikros/components/definition"
+
+	"github.com/mikros-dev/mikros-cli/internal/plugin/client"
+	"github.com/mikros-dev/mikros-cli/internal/settings"
+	"github.com/mikros-dev/mikros-cli/internal/ui"
+)
+
+const (
+	reviewGenerate      = "generate"
+	reviewBase          = "base"
+	reviewServiceType   = "service-type"
+	reviewFeaturePrefix = "feature:"
+)
+
+// runReview presents a summary of every answer collected so far and lets the
+// user go back to any section before confirming the generation. It returns the
+// service plugin matching the (possibly edited) service type.
+func runReview(cfg *settings.Settings, answers *surveyAnswers, svc *client.Service) (*client.Service, error) {
+	for {
+		choice, err := runReviewForm(cfg, answers, svc)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case choice == reviewGenerate:
+			return svc, nil
+
+		case choice == reviewBase:
+			s, err := editBaseSection(cfg, answers, svc)
+			if err != nil {
+				return nil, err
+			}
+			svc = s
+
+		case choice == reviewServiceType:
+			s, err := runServiceTypeSurvey(cfg, answers)
+			if err != nil {
+				return nil, err
+			}
+			svc = s
+
+		case strings.HasPrefix(choice, reviewFeaturePrefix):
+			name := strings.TrimPrefix(choice, reviewFeaturePrefix)
+			if err := runFeatureSurvey(cfg, answers, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func runReviewForm(cfg *settings.Settings, answers *surveyAnswers, svc *client.Service) (string, error) {
+	var (
+		choice  string
+		options = []huh.Option[string]{
+			huh.NewOption("Confirm and generate the service", reviewGenerate),
+			huh.NewOption("Edit service settings", reviewBase),
+		}
+	)
+
+	if hasServiceTypeSection(answers, svc) {
+		options = append(options, huh.NewOption(fmt.Sprintf("Edit %s answers", answers.Type), reviewServiceType))
+	}
+	for _, name := range answers.Features {
+		if _, ok := answers.FeatureAnswers(name); ok {
+			options = append(options, huh.NewOption(fmt.Sprintf("Edit %s answers", name), reviewFeaturePrefix+name))
+		}
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Review").
+				Description(reviewSummary(answers, svc)),
+
+			huh.NewSelect[string]().
+				Title("Choose an option").
+				Options(options...).
+				Value(&choice),
+		),
+	).
+		WithAccessible(cfg.UI.Accessible).
+		WithTheme(cfg.GetTheme())
+
+	if err := ui.RunForm(form, "review option"); err != nil {
+		return "", err
+	}
+
+	return choice, nil
+}
+
+func hasServiceTypeSection(answers *surveyAnswers, svc *client.Service) bool {
+	return svc != nil || answers.Type == definition.ServiceTypeHTTP.String()
+}
+
+// editBaseSection executes the base survey again and re-runs only the surveys
+// that depend on answers that were changed.
+func editBaseSection(cfg *settings.Settings, answers *surveyAnswers, svc *client.Service) (*client.Service, error) {
+	var (
+		previousType     = answers.Type
+		previousFeatures = slices.Clone(answers.Features)
+	)
+
+	if err := runBaseSurvey(cfg, answers); err != nil {
+		return nil, err
+	}
+
+	if answers.Type != previousType {
+		answers.ResetServiceType()
+
+		s, err := runServiceTypeSurvey(cfg, answers)
+		if err != nil {
+			return nil, err
+		}
+		svc = s
+	}
+
+	for _, name := range previousFeatures {
+		if !slices.Contains(answers.Features, name) {
+			answers.RemoveFeature(name)
+		}
+	}
+
+	for _, name := range answers.Features {
+		if !slices.Contains(previousFeatures, name) {
+			if err := runFeatureSurvey(cfg, answers, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return svc, nil
+}
+
+func reviewSummary(answers *surveyAnswers, svc *client.Service) string {
+	var b strings.Builder
+
+	b.WriteString("Service\n")
+	writeAnswer(&b, 1, "Name", answers.Name)
+	writeAnswer(&b, 1, "Type", answers.Type)
+	writeAnswer(&b, 1, "Language", answers.Language)
+	writeAnswer(&b, 1, "Version", answers.Version)
+	writeAnswer(&b, 1, "Product", answers.Product)
+	writeAnswer(&b, 1, "Lifecycle", strings.Join(answers.Lifecycle, ", "))
+	writeAnswer(&b, 1, "Features", strings.Join(answers.Features, ", "))
+
+	if hasServiceTypeSection(answers, svc) {
+		fmt.Fprintf(&b, "\n%s\n", answers.Type)
+		if answers.HTTPType != "" {
+			writeAnswer(&b, 1, "HTTP type", answers.HTTPType)
+		}
+		writeAnswers(&b, 1, answers.ServiceAnswers())
+	}
+
+	for _, name := range answers.Features {
+		if a, ok := answers.FeatureAnswers(name); ok {
+			fmt.Fprintf(&b, "\n%s\n", name)
+			writeAnswers(&b, 1, a)
+		}
+	}
+
+	return b.String()
+}
+
+func writeAnswers(b *strings.Builder, level int, values map[string]interface{}) {
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		switch v := values[k].(type) {
+		case map[string]interface{}:
+			writeAnswer(b, level, k, "")
+			writeAnswers(b, level+1, v)
+
+		case map[string]map[string]interface{}:
+			writeAnswer(b, level, k, "")
+			for _, name := range slices.Sorted(maps.Keys(v)) {
+				writeAnswer(b, level+1, name, "")
+				writeAnswers(b, level+2, v[name])
+			}
+
+		case []map[string]interface{}:
+			writeAnswer(b, level, k, "")
+			for i, answers := range v {
+				writeAnswer(b, level+1, fmt.Sprintf("#%d", i+1), "")
+				writeAnswers(b, level+2, answers)
+			}
+
+		case []string:
+			writeAnswer(b, level, k, strings.Join(v, ", "))
+
+		default:
+			writeAnswer(b, level, k, fmt.Sprintf("%v", v))
+		}
+	}
+}
+
+func writeAnswer(b *strings.Builder, level int, key, value string) {
+	fmt.Fprintf(b, "%s%s: %s\n", strings.Repeat("  ", level), key, value)
+}
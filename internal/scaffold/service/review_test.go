@@ -0,0 +1,35 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteAnswers(t *testing.T) {
+	values := map[string]interface{}{
+		"port": 8080,
+		"host": "localhost",
+		"follow-up": map[string]map[string]interface{}{
+			"tls":   {"key": "tls.key", "cert": "tls.crt"},
+			"cache": {"ttl": "1m"},
+		},
+	}
+	want := `follow-up: 
+  cache: 
+    ttl: 1m
+  tls: 
+    cert: tls.crt
+    key: tls.key
+host: localhost
+port: 8080
+`
+
+	// Maps are iterated in random order, so it is written a few times.
+	for range 10 {
+		var b strings.Builder
+		writeAnswers(&b, 0, values)
+		if got := b.String(); got != want {
+			t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+		}
+	}
+}
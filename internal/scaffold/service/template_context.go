@@ -19,7 +19,9 @@ type TemplateContext struct {
 	ExternalServicesArg      string
 	NewServiceArgs           string
 	ServiceName              string
+	ModulePath               string
 	GrpcMethods              []*protobuf.Method
+	Handlers                 []*Handler
 	Imports                  map[string][]ImportContext
 	ServiceTypeCustomAnswers interface{}
 	PluginData               interface{}
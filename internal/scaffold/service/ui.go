@@ -2,32 +2,80 @@ package service
 
 import (
 	"errors"
+	"slices"
 	"sort"
 
 	"github.com/charmbracelet/huh"
 	"github.com/mikros-dev/mikros/components/definition"
 
+	"github.com/mikros-dev/mikros-cli/internal/history"
 	"github.com/mikros-dev/mikros-cli/internal/plugin"
 	"github.com/mikros-dev/mikros-cli/internal/plugin/client"
 	"github.com/mikros-dev/mikros-cli/internal/settings"
 	"github.com/mikros-dev/mikros-cli/internal/ui"
 )
 
-func runSurvey(cfg *settings.Settings, protoFilename string) (*surveyAnswers, error) {
-	answers, err := newSurveyAnswers(protoFilename)
+func runSurvey(cfg *settings.Settings, options *NewOptions, previous *history.Answers) (*surveyAnswers, error) {
+	answers, err := newSurveyAnswers(options, previous)
 	if err != nil {
 		return nil, err
 	}
 
+	if !ui.IsInteractive() {
+		return nil, missingInputs(cfg, answers)
+	}
+
+	if err := runBaseSurvey(cfg, answers); err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+// missingInputs returns an error describing everything that creating the
+// service asks for: the base survey, the surveys of its type and features,
+// as they are known before asking anything, and the review.
+func missingInputs(cfg *settings.Settings, answers *surveyAnswers) error {
+	inputs := ui.NewInputs("service name", "service type", "language", "version", "product", "lifecycle", "features")
+
+	if answers.Type == definition.ServiceTypeHTTP.String() {
+		inputs = append(inputs, ui.Input{Name: "HTTP service type"})
+	}
+	// Plugins that fail here only leave their questions out of the error.
+	if svc, err := plugin.GetServicePlugin(cfg, answers.Type); err == nil && svc != nil {
+		if s, err := svc.GetSurvey(); err == nil && s != nil {
+			inputs = append(inputs, ui.SurveyInputs(answers.Type, s)...)
+		}
+	}
+	for _, name := range answers.Features {
+		if f, err := plugin.GetFeaturePlugin(cfg, name); err == nil && f != nil {
+			if s, err := f.GetSurvey(); err == nil && s != nil {
+				inputs = append(inputs, ui.SurveyInputs(name, s)...)
+			}
+		}
+	}
+	inputs = append(inputs, ui.Input{Name: "review option"})
+
+	return ui.RequireInputs(inputs...)
+}
+
+// runBaseSurvey executes the base service survey using answers current values
+// as the form initial state.
+func runBaseSurvey(cfg *settings.Settings, answers *surveyAnswers) error {
 	questions, err := getBaseQuestions(answers, cfg)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	featureNames, err := plugin.GetFeaturesUINames(cfg)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	// Only features that are still available can be selected
+	answers.Features = slices.DeleteFunc(answers.Features, func(name string) bool {
+		return !slices.Contains(featureNames, name)
+	})
+
 	if len(featureNames) > 0 {
 		features := make([]huh.Option[string], len(featureNames))
 		for i, f := range featureNames {
@@ -45,11 +93,7 @@ func runSurvey(cfg *settings.Settings, protoFilename string) (*surveyAnswers, er
 		WithTheme(cfg.GetTheme()).
 		WithAccessible(cfg.UI.Accessible)
 
-	if err := form.Run(); err != nil {
-		return nil, err
-	}
-
-	return answers, nil
+	return ui.RunForm(form, "service name", "service type", "language", "version", "product", "lifecycle", "features")
 }
 
 func getBaseQuestions(answers *surveyAnswers, cfg *settings.Settings) ([]huh.Field, error) {
@@ -108,23 +152,35 @@ func getBaseQuestions(answers *surveyAnswers, cfg *settings.Settings) ([]huh.Fie
 }
 
 func getSupportedServiceTypes(cfg *settings.Settings) ([]huh.Option[string], error) {
-	types := []huh.Option[string]{
-		huh.NewOption(definition.ServiceTypeGRPC.String(), definition.ServiceTypeGRPC.String()),
-		huh.NewOption(definition.ServiceTypeHTTP.String(), definition.ServiceTypeHTTP.String()),
-		huh.NewOption(definition.ServiceTypeWorker.String(), definition.ServiceTypeWorker.String()),
-		huh.NewOption(definition.ServiceTypeScript.String(), definition.ServiceTypeScript.String()),
+	supportedTypes, err := SupportedServiceTypes(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]huh.Option[string], len(supportedTypes))
+	for i, t := range supportedTypes {
+		types[i] = huh.NewOption(t, t)
+	}
+
+	return types, nil
+}
+
+// SupportedServiceTypes returns the service types that can be created, i.e.,
+// the ones supported by the framework and the ones provided by plugins.
+func SupportedServiceTypes(cfg *settings.Settings) ([]string, error) {
+	types := []string{
+		definition.ServiceTypeGRPC.String(),
+		definition.ServiceTypeHTTP.String(),
+		definition.ServiceTypeWorker.String(),
+		definition.ServiceTypeScript.String(),
 	}
 
 	newTypes, err := plugin.GetNewServiceKinds(cfg)
 	if err != nil {
 		return nil, err
 	}
-	for _, t := range newTypes {
-		types = append(types, huh.NewOption(t, t))
-	}
-	sort.Slice(types, func(i, j int) bool {
-		return types[i].String() < types[j].String()
-	})
+	types = append(types, newTypes...)
+	sort.Strings(types)
 
 	return types, nil
 }
@@ -153,6 +209,7 @@ func runServiceTypeSurvey(cfg *settings.Settings, answers *surveyAnswers) (*clie
 	response, err := ui.RunFormFromSurvey(answers.Type, svcSurvey, &ui.FormOptions{
 		Theme:      cfg.GetTheme(),
 		Accessible: cfg.UI.Accessible,
+		Defaults:   answers.PluginDefaults(answers.Type, answers.ServiceAnswers()),
 	})
 	if err != nil {
 		return nil, err
@@ -189,44 +246,54 @@ func runCoreServiceTypeSurvey(cfg *settings.Settings, answers *surveyAnswers) er
 		WithAccessible(cfg.UI.Accessible).
 		WithTheme(cfg.GetTheme())
 
-	return form.Run()
+	return ui.RunForm(form, "HTTP service type")
 }
 
-// runFeatureSurvey executes the survey that a feature may have implemented.
-func runFeatureSurvey(cfg *settings.Settings, name string) (string, interface{}, error) {
-	f, err := plugin.GetFeaturePlugin(cfg, name)
+// runFeatureSurvey executes the survey that a feature may have implemented
+// and stores its results inside answers.
+func runFeatureSurvey(cfg *settings.Settings, answers *surveyAnswers, uiName string) error {
+	f, err := plugin.GetFeaturePlugin(cfg, uiName)
 	if err != nil {
-		return "", nil, err
+		return err
 	}
 	if f == nil {
-		return "", nil, nil
+		return nil
 	}
 
 	s, err := f.GetSurvey()
 	if err != nil {
-		return "", nil, err
+		return err
 	}
 	if s == nil {
-		return "", nil, nil
+		return nil
 	}
 
-	res, err := ui.RunFormFromSurvey(name, s, &ui.FormOptions{
+	current, _ := answers.FeatureAnswers(uiName)
+	res, err := ui.RunFormFromSurvey(uiName, s, &ui.FormOptions{
 		Theme:      cfg.GetTheme(),
 		Accessible: cfg.UI.Accessible,
+		Defaults:   answers.PluginDefaults(uiName, current),
 	})
 	if err != nil {
-		return "", nil, err
+		return err
 	}
 
 	defs, err := f.ValidateAnswers(res)
 	if err != nil {
-		return "", nil, err
+		return err
 	}
 
 	featureName, err := f.GetName()
 	if err != nil {
-		return "", nil, err
+		return err
+	}
+
+	// Answers from a previous execution must not survive
+	answers.RemoveFeature(uiName)
+	answers.SetFeatureAnswers(uiName, featureName, res)
+	if len(defs) != 0 {
+		answers.AddFeatureDefinitions(featureName, defs)
 	}
 
-	return featureName, defs, nil
+	return nil
 }
@@ -5,10 +5,8 @@ import (
 	"encoding/hex"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/BurntSushi/toml"
-	"github.com/charmbracelet/huh"
 	"github.com/creasty/defaults"
 
 	"github.com/mikros-dev/mikros-cli/internal/fs"
@@ -29,6 +27,9 @@ type Settings struct {
 // Path represents a configuration structure related to plugin directories.
 type Path struct {
 	Plugins Plugins `toml:"plugins"`
+
+	// Packs specifies the path to the template packs directory.
+	Packs string `toml:"packs" default:"$HOME/.mikros/packs"`
 }
 
 // Plugins represents the configuration structure for plugin directory paths.
@@ -43,15 +44,27 @@ type Plugins struct {
 // Profile represents a configuration structure tied to a specific project.
 type Profile struct {
 	Project Project `toml:"project"`
+
+	// TemplatesDir specifies a directory with templates that override (or
+	// extend) the built-in ones used to create new projects.
+	TemplatesDir string `toml:"templates_dir,omitempty"`
 }
 
 // Project represents configuration details for a project, including protobuf
 // monorepo and template definitions.
 type Project struct {
 	ProtobufMonorepo ProtobufMonorepo `toml:"protobuf_monorepo"`
+	Service          Service          `toml:"service"`
 	Templates        Templates        `toml:"templates"`
 }
 
+// Service represents the configuration for new services.
+type Service struct {
+	// VcsPath is the prefix of Go module paths of services created with
+	// bare names, i.e., names that are not fully qualified.
+	VcsPath string `toml:"vcs_path,omitempty"`
+}
+
 // ProtobufMonorepo represents the configuration for a protobuf monorepo.
 type ProtobufMonorepo struct {
 	RepositoryName string `toml:"repository_name" default:"protobuf-workspace"`
@@ -78,6 +91,10 @@ type UI struct {
 
 	// Accessible indicates whether accessibility features are enabled.
 	Accessible bool `toml:"accessible"`
+
+	// Themes holds user-defined color themes, indexed by their names, that
+	// can be selected with Theme.
+	Themes map[string]Theme `toml:"themes,omitempty"`
 }
 
 // Load initializes and retrieves the application settings using default
@@ -107,10 +124,27 @@ func NewDefault() (*Settings, error) {
 
 	cfg.Paths.Plugins.Services = os.ExpandEnv(cfg.Paths.Plugins.Services)
 	cfg.Paths.Plugins.Features = os.ExpandEnv(cfg.Paths.Plugins.Features)
+	cfg.Paths.Packs = os.ExpandEnv(cfg.Paths.Packs)
 
 	return cfg, nil
 }
 
+// GetProfile returns the profile named name. The application profile is
+// returned for the "default" name or when the profile does not exist.
+func (s *Settings) GetProfile(name string) *Profile {
+	profile := &s.App
+	if name == "default" {
+		return profile
+	}
+
+	d, ok := s.Profile[name]
+	if !ok {
+		return profile
+	}
+
+	return &d
+}
+
 // FileExists checks if the settings file exists and returns its expanded path.
 func FileExists() (string, bool) {
 	name := os.ExpandEnv(settingsFilename)
@@ -138,23 +172,6 @@ func (s *Settings) Write() error {
 	return en.Encode(s)
 }
 
-// GetTheme returns the appropriate theme based on the UI.Theme value,
-// defaulting to a base theme if no match is found.
-func (s *Settings) GetTheme() *huh.Theme {
-	switch strings.ToLower(s.UI.Theme) {
-	case "charm":
-		return huh.ThemeCharm()
-	case "dracula":
-		return huh.ThemeDracula()
-	case "catppuccin":
-		return huh.ThemeCatppuccin()
-	case "base16":
-		return huh.ThemeBase16()
-	}
-
-	return huh.ThemeBase()
-}
-
 // Hash computes the SHA-256 hash of the Settings instance serialized in TOML
 // format and returns it as a hex string.
 func (s *Settings) Hash() (string, error) {
@@ -0,0 +1,130 @@
+package settings
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	// builtinThemeNames holds the names of the themes provided by huh, in
+	// the order that they are presented to the user.
+	builtinThemeNames = []string{"base16", "charm", "dracula", "catppuccin"}
+
+	builtinThemes = map[string]func() *huh.Theme{
+		"base16":     huh.ThemeBase16,
+		"charm":      huh.ThemeCharm,
+		"dracula":    huh.ThemeDracula,
+		"catppuccin": huh.ThemeCatppuccin,
+	}
+)
+
+// Theme represents a user-defined color theme. Colors can be ANSI color
+// numbers (like "212") or hex values (like "#F780E2"). Empty colors keep the
+// values of the base theme.
+type Theme struct {
+	// Base sets the built-in theme used as starting point.
+	Base string `toml:"base,omitempty"`
+
+	// Title sets the color of titles.
+	Title string `toml:"title,omitempty"`
+
+	// Focused sets the color of the focused field borders, selectors and
+	// buttons.
+	Focused string `toml:"focused,omitempty"`
+
+	// Blurred sets the color of fields that are not focused.
+	Blurred string `toml:"blurred,omitempty"`
+
+	// Error sets the color of error indicators and messages.
+	Error string `toml:"error,omitempty"`
+
+	// Selection sets the color of selected options.
+	Selection string `toml:"selection,omitempty"`
+}
+
+// GetTheme returns the appropriate theme based on the UI.Theme value, which
+// can be a built-in theme or a theme defined in the settings file, defaulting
+// to a base theme if no match is found.
+func (s *Settings) GetTheme() *huh.Theme {
+	if t, ok := builtinThemes[strings.ToLower(s.UI.Theme)]; ok {
+		return t()
+	}
+
+	if t, ok := s.UI.Themes[s.UI.Theme]; ok {
+		return t.build()
+	}
+
+	return huh.ThemeBase()
+}
+
+// ThemeNames returns the names of all available themes, built-in themes
+// first and then the ones defined in the settings file.
+func (s *Settings) ThemeNames() []string {
+	var custom []string
+	for name := range s.UI.Themes {
+		if _, ok := builtinThemes[strings.ToLower(name)]; !ok {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+
+	return append(append([]string{}, builtinThemeNames...), custom...)
+}
+
+func (t Theme) build() *huh.Theme {
+	theme := huh.ThemeBase()
+	if base, ok := builtinThemes[strings.ToLower(t.Base)]; ok {
+		theme = base()
+	}
+
+	if c := t.Title; c != "" {
+		color := lipgloss.Color(c)
+		theme.Focused.Title = theme.Focused.Title.Foreground(color)
+		theme.Focused.NoteTitle = theme.Focused.NoteTitle.Foreground(color)
+		theme.Blurred.Title = theme.Blurred.Title.Foreground(color)
+		theme.Blurred.NoteTitle = theme.Blurred.NoteTitle.Foreground(color)
+	}
+
+	if c := t.Focused; c != "" {
+		color := lipgloss.Color(c)
+		theme.Focused.Base = theme.Focused.Base.BorderForeground(color)
+		theme.Focused.SelectSelector = theme.Focused.SelectSelector.Foreground(color)
+		theme.Focused.MultiSelectSelector = theme.Focused.MultiSelectSelector.Foreground(color)
+		theme.Focused.NextIndicator = theme.Focused.NextIndicator.Foreground(color)
+		theme.Focused.PrevIndicator = theme.Focused.PrevIndicator.Foreground(color)
+		theme.Focused.TextInput.Prompt = theme.Focused.TextInput.Prompt.Foreground(color)
+		theme.Focused.TextInput.Cursor = theme.Focused.TextInput.Cursor.Foreground(color)
+		theme.Focused.FocusedButton = theme.Focused.FocusedButton.Background(color)
+		theme.Focused.Next = theme.Focused.FocusedButton
+	}
+
+	if c := t.Blurred; c != "" {
+		color := lipgloss.Color(c)
+		theme.Blurred.Title = theme.Blurred.Title.Foreground(color)
+		theme.Blurred.Description = theme.Blurred.Description.Foreground(color)
+		theme.Blurred.Option = theme.Blurred.Option.Foreground(color)
+		theme.Blurred.UnselectedOption = theme.Blurred.UnselectedOption.Foreground(color)
+		theme.Blurred.TextInput.Text = theme.Blurred.TextInput.Text.Foreground(color)
+	}
+
+	if c := t.Error; c != "" {
+		color := lipgloss.Color(c)
+		theme.Focused.ErrorIndicator = theme.Focused.ErrorIndicator.Foreground(color)
+		theme.Focused.ErrorMessage = theme.Focused.ErrorMessage.Foreground(color)
+		theme.Blurred.ErrorIndicator = theme.Blurred.ErrorIndicator.Foreground(color)
+		theme.Blurred.ErrorMessage = theme.Blurred.ErrorMessage.Foreground(color)
+	}
+
+	if c := t.Selection; c != "" {
+		color := lipgloss.Color(c)
+		theme.Focused.SelectedOption = theme.Focused.SelectedOption.Foreground(color)
+		theme.Focused.SelectedPrefix = theme.Focused.SelectedPrefix.Foreground(color)
+		theme.Blurred.SelectedOption = theme.Blurred.SelectedOption.Foreground(color)
+		theme.Blurred.SelectedPrefix = theme.Blurred.SelectedPrefix.Foreground(color)
+	}
+
+	return theme
+}
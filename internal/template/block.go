@@ -8,11 +8,10 @@ import (
 
 // ParseBlock parses a block of text using a template.
 func ParseBlock(block string, api map[string]interface{}, data interface{}) (string, error) {
-	helperAPI := defaultAPI
-	for k, v := range api {
-		helperAPI[k] = v
-	}
+	return parseBlock(block, newHelperAPI(api), data)
+}
 
+func parseBlock(block string, helperAPI template.FuncMap, data interface{}) (string, error) {
 	tpl, err := template.New("custom").Funcs(helperAPI).Parse(block)
 	if err != nil {
 		return "", err
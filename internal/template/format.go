@@ -0,0 +1,47 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"path/filepath"
+
+	"golang.org/x/tools/imports"
+)
+
+// formatSource formats the content generated by a template according to its
+// output file type. Currently, only Go sources are formatted, which also have
+// their imports grouped and the unused ones removed.
+func formatSource(filename string, src []byte) ([]byte, error) {
+	if filepath.Ext(filename) != ".go" {
+		return src, nil
+	}
+
+	out, err := imports.Process(filename, src, &imports.Options{
+		Comments:  true,
+		TabIndent: true,
+		TabWidth:  8,
+	})
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) && len(list) > 0 {
+			pos := list[0].Pos
+			return nil, fmt.Errorf("generated invalid Go code at line %d: %s\n\t%s",
+				pos.Line, list[0].Msg, sourceLine(src, pos.Line))
+		}
+
+		return nil, fmt.Errorf("could not format output: %w", err)
+	}
+
+	return out, nil
+}
+
+func sourceLine(src []byte, line int) string {
+	lines := bytes.Split(src, []byte("\n"))
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	return string(bytes.TrimSpace(lines[line-1]))
+}
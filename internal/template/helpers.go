@@ -0,0 +1,421 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/mikros-dev/mikros-cli/internal/protobuf"
+)
+
+// defaultAPI holds the functions available to every template. It must never be
+// changed, since sessions use their own copies of it. Adding or changing
+// functions requires incrementing wire.TemplateAPIVersion.
+var defaultAPI = template.FuncMap{
+	"toCamel":      strcase.ToCamel,
+	"toSnake":      strcase.ToSnake,
+	"toUpperSnake": strcase.ToScreamingSnake,
+	"basename":     path.Base,
+	"toKebab":      strcase.ToKebab,
+	"pluralize":    pluralize,
+	"singularize":  singularize,
+	"lower":        strings.ToLower,
+	"upper":        strings.ToUpper,
+	"title":        title,
+	"join":         join,
+	"split":        split,
+	"default":      defaultValue,
+	"coalesce":     coalesce,
+	"dict":         dict,
+	"list":         list,
+	"indent":       indent,
+	"nindent":      nindent,
+	"goIdent":      goIdent,
+	"quote":        strconv.Quote,
+	"squote":       squote,
+	"protoToGo":    protoToGo,
+}
+
+var (
+	irregularPlurals = map[string]string{
+		"child":  "children",
+		"person": "people",
+		"man":    "men",
+		"woman":  "women",
+		"mouse":  "mice",
+		"goose":  "geese",
+		"foot":   "feet",
+		"tooth":  "teeth",
+		"datum":  "data",
+		"index":  "indices",
+		"status": "statuses",
+		"knife":  "knives",
+		"life":   "lives",
+		"wife":   "wives",
+	}
+
+	uncountables = map[string]bool{
+		"data":        true,
+		"equipment":   true,
+		"information": true,
+		"metadata":    true,
+		"money":       true,
+		"news":        true,
+		"series":      true,
+		"species":     true,
+	}
+
+	protoScalarTypes = map[string]string{
+		"double":   "float64",
+		"float":    "float32",
+		"int32":    "int32",
+		"sint32":   "int32",
+		"sfixed32": "int32",
+		"int64":    "int64",
+		"sint64":   "int64",
+		"sfixed64": "int64",
+		"uint32":   "uint32",
+		"fixed32":  "uint32",
+		"uint64":   "uint64",
+		"fixed64":  "uint64",
+		"bool":     "bool",
+		"string":   "string",
+		"bytes":    "[]byte",
+	}
+
+	protoWellKnownTypes = map[string]string{
+		"google.protobuf.Timestamp":   "*timestamppb.Timestamp",
+		"google.protobuf.Duration":    "*durationpb.Duration",
+		"google.protobuf.Empty":       "*emptypb.Empty",
+		"google.protobuf.Any":         "*anypb.Any",
+		"google.protobuf.Struct":      "*structpb.Struct",
+		"google.protobuf.Value":       "*structpb.Value",
+		"google.protobuf.FieldMask":   "*fieldmaskpb.FieldMask",
+		"google.protobuf.StringValue": "*wrapperspb.StringValue",
+		"google.protobuf.BoolValue":   "*wrapperspb.BoolValue",
+		"google.protobuf.Int32Value":  "*wrapperspb.Int32Value",
+		"google.protobuf.Int64Value":  "*wrapperspb.Int64Value",
+		"google.protobuf.UInt32Value": "*wrapperspb.UInt32Value",
+		"google.protobuf.UInt64Value": "*wrapperspb.UInt64Value",
+		"google.protobuf.FloatValue":  "*wrapperspb.FloatValue",
+		"google.protobuf.DoubleValue": "*wrapperspb.DoubleValue",
+		"google.protobuf.BytesValue":  "*wrapperspb.BytesValue",
+	}
+)
+
+// pluralize returns the plural form of an English word, keeping the case of
+// its first letter.
+func pluralize(word string) string {
+	return inflect(word, func(w string) string {
+		if plural, ok := irregularPlurals[w]; ok {
+			return plural
+		}
+
+		switch {
+		case hasAnySuffix(w, "s", "x", "z", "ch", "sh"):
+			return w + "es"
+		case strings.HasSuffix(w, "y") && !endsWithVowelAnd(w, "y"):
+			return strings.TrimSuffix(w, "y") + "ies"
+		case strings.HasSuffix(w, "fe"):
+			return strings.TrimSuffix(w, "fe") + "ves"
+		case strings.HasSuffix(w, "f") && !strings.HasSuffix(w, "ff"):
+			return strings.TrimSuffix(w, "f") + "ves"
+		}
+
+		return w + "s"
+	})
+}
+
+// singularize returns the singular form of an English word, keeping the case
+// of its first letter. Words that are already singular, like the irregular
+// ones or the ones ending with "us", "is" or "ss", are kept.
+func singularize(word string) string {
+	return inflect(word, func(w string) string {
+		if _, ok := irregularPlurals[w]; ok {
+			return w
+		}
+		for singular, plural := range irregularPlurals {
+			if w == plural {
+				return singular
+			}
+		}
+
+		switch {
+		case hasAnySuffix(w, "us", "is", "ss"):
+			return w
+		case strings.HasSuffix(w, "ies") && len(w) > 3:
+			return strings.TrimSuffix(w, "ies") + "y"
+		case hasAnySuffix(w, "lves", "eaves"):
+			return strings.TrimSuffix(w, "ves") + "f"
+		case hasAnySuffix(w, "sses", "xes", "zes", "ches", "shes"):
+			return strings.TrimSuffix(w, "es")
+		case strings.HasSuffix(w, "uses") && len(w) > 4 && !endsWithVowelAnd(w, "uses"):
+			// buses, statuses, viruses (but houses, causes).
+			return strings.TrimSuffix(w, "es")
+		case strings.HasSuffix(w, "s"):
+			return strings.TrimSuffix(w, "s")
+		}
+
+		return w
+	})
+}
+
+// inflect applies fn to the last word of s (considering camel case, snake
+// case and kebab case words), keeping its original capitalization.
+func inflect(s string, fn func(string) string) string {
+	if s == "" {
+		return s
+	}
+
+	start := strings.LastIndexAny(s, "_- ") + 1
+	for i := len(s) - 1; i > start; i-- {
+		// Camel case words start with an upper case letter following a
+		// lower case one, or ending an acronym (HTTPServer), so that upper
+		// case words are kept whole.
+		if unicode.IsUpper(rune(s[i])) && (unicode.IsLower(rune(s[i-1])) ||
+			(i+1 < len(s) && unicode.IsLower(rune(s[i+1])))) {
+			start = i
+			break
+		}
+	}
+
+	var (
+		prefix = s[:start]
+		word   = s[start:]
+		lower  = strings.ToLower(word)
+	)
+
+	if uncountables[lower] {
+		return s
+	}
+
+	result := fn(lower)
+	switch {
+	case result == "":
+		return s
+	case word == strings.ToUpper(word) && len(word) > 1:
+		result = strings.ToUpper(result)
+	case unicode.IsUpper(rune(word[0])):
+		result = strings.ToUpper(result[:1]) + result[1:]
+	}
+
+	return prefix + result
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func endsWithVowelAnd(s, suffix string) bool {
+	s = strings.TrimSuffix(s, suffix)
+	return s != "" && strings.ContainsRune("aeiou", rune(s[len(s)-1]))
+}
+
+// title capitalizes the first letter of every word of s.
+func title(s string) string {
+	var (
+		b     strings.Builder
+		start = true
+	)
+
+	for _, r := range s {
+		if start {
+			r = unicode.ToUpper(r)
+		}
+		start = unicode.IsSpace(r) || r == '_' || r == '-'
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// join concatenates the elements of a list (of any type) using sep between
+// them. The list is the last argument so it can be used in pipelines.
+func join(sep string, values interface{}) (string, error) {
+	items, err := toSlice(values)
+	if err != nil {
+		return "", err
+	}
+
+	s := make([]string, len(items))
+	for i, item := range items {
+		s[i] = fmt.Sprint(item)
+	}
+
+	return strings.Join(s, sep), nil
+}
+
+// split slices s into all substrings separated by sep. The string is the
+// last argument so it can be used in pipelines.
+func split(sep, s string) []string {
+	return strings.Split(s, sep)
+}
+
+// defaultValue returns value when it is not empty, or def otherwise. The
+// value is the last argument so it can be used in pipelines.
+func defaultValue(def, value interface{}) interface{} {
+	if isEmpty(value) {
+		return def
+	}
+
+	return value
+}
+
+// coalesce returns the first value that is not empty.
+func coalesce(values ...interface{}) interface{} {
+	for _, v := range values {
+		if !isEmpty(v) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// dict builds a map from a list of key and value pairs.
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict expects pairs of keys and values")
+	}
+
+	d := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict keys must be strings, got %T", pairs[i])
+		}
+		d[key] = pairs[i+1]
+	}
+
+	return d, nil
+}
+
+// list builds a list from its arguments.
+func list(values ...interface{}) []interface{} {
+	return values
+}
+
+// indent adds spaces spaces at the beginning of every line of s.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// nindent is like indent, but also adds a new line before s.
+func nindent(spaces int, s string) string {
+	return "\n" + indent(spaces, s)
+}
+
+// goIdent sanitizes s into a valid Go identifier, replacing invalid characters
+// with underscores and avoiding keywords and leading digits.
+func goIdent(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+			b.WriteRune(r)
+		case unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	ident := b.String()
+	switch {
+	case ident == "":
+		return "_"
+	case token.IsKeyword(ident):
+		return ident + "_"
+	}
+
+	return ident
+}
+
+// squote wraps s in single quotes, escaping single quotes inside it by
+// doubling them, as YAML and SQL do.
+func squote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// protoToGo returns the Go type generated for a protobuf field type. Scalar
+// and well-known types are mapped to their Go types, and other types to the
+// name of their generated Go types: pointers for messages and values for
+// enums, whose kind must be given as "enum". Leading lower case components
+// of the name are taken as its package, and names of nested types are joined
+// by '_', like protoc-gen-go does.
+func protoToGo(protoType string, kind ...string) string {
+	protoType = strings.TrimPrefix(protoType, ".")
+
+	if t, ok := protoScalarTypes[protoType]; ok {
+		return t
+	}
+	if t, ok := protoWellKnownTypes[protoType]; ok {
+		return t
+	}
+
+	var (
+		parts = strings.Split(protoType, ".")
+		start = len(parts) - 1
+	)
+	for i, part := range parts {
+		if part != "" && unicode.IsUpper(rune(part[0])) {
+			start = i
+			break
+		}
+	}
+
+	name := protobuf.GoCamelCase(strings.Join(parts[start:], "."))
+	if len(kind) > 0 && kind[0] == string(protobuf.TypeKindEnum) {
+		return name
+	}
+
+	return "*" + name
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+func toSlice(values interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a list, got %T", values)
+	}
+
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+
+	return items, nil
+}
@@ -0,0 +1,153 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/iancoleman/strcase"
+)
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "invoice", want: "invoices"},
+		{word: "address", want: "addresses"},
+		{word: "box", want: "boxes"},
+		{word: "batch", want: "batches"},
+		{word: "category", want: "categories"},
+		{word: "key", want: "keys"},
+		{word: "knife", want: "knives"},
+		{word: "leaf", want: "leaves"},
+		{word: "cliff", want: "cliffs"},
+		{word: "person", want: "people"},
+		{word: "status", want: "statuses"},
+		{word: "bus", want: "buses"},
+		{word: "data", want: "data"},
+		{word: "Invoice", want: "Invoices"},
+		{word: "INVOICE", want: "INVOICES"},
+		{word: "orderItem", want: "orderItems"},
+		{word: "HTTPServer", want: "HTTPServers"},
+		{word: "order_person", want: "order_people"},
+		{word: "user-category", want: "user-categories"},
+		{word: "UserMetadata", want: "UserMetadata"},
+		{word: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := pluralize(tt.word); got != tt.want {
+			t.Errorf("pluralize(%q): got %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "invoices", want: "invoice"},
+		{word: "addresses", want: "address"},
+		{word: "boxes", want: "box"},
+		{word: "batches", want: "batch"},
+		{word: "categories", want: "category"},
+		{word: "keys", want: "key"},
+		{word: "knives", want: "knife"},
+		{word: "wolves", want: "wolf"},
+		{word: "people", want: "person"},
+		{word: "statuses", want: "status"},
+		{word: "buses", want: "bus"},
+		{word: "houses", want: "house"},
+		{word: "uses", want: "use"},
+		{word: "responses", want: "response"},
+		{word: "status", want: "status"},
+		{word: "person", want: "person"},
+		{word: "bus", want: "bus"},
+		{word: "analysis", want: "analysis"},
+		{word: "address", want: "address"},
+		{word: "invoice", want: "invoice"},
+		{word: "news", want: "news"},
+		{word: "Invoices", want: "Invoice"},
+		{word: "INVOICES", want: "INVOICE"},
+		{word: "orderStatus", want: "orderStatus"},
+		{word: "order_items", want: "order_item"},
+		{word: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := singularize(tt.word); got != tt.want {
+			t.Errorf("singularize(%q): got %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSquote(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "invoice", want: "'invoice'"},
+		{s: "", want: "''"},
+		{s: "it's", want: "'it''s'"},
+		{s: `a "b" \c`, want: `'a "b" \c'`},
+	}
+
+	for _, tt := range tests {
+		if got := squote(tt.s); got != tt.want {
+			t.Errorf("squote(%q): got %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestProtoToGo(t *testing.T) {
+	tests := []struct {
+		protoType string
+		kind      []string
+		want      string
+	}{
+		{protoType: "string", want: "string"},
+		{protoType: "sint64", want: "int64"},
+		{protoType: "fixed32", want: "uint32"},
+		{protoType: "bytes", want: "[]byte"},
+		{protoType: "google.protobuf.Timestamp", want: "*timestamppb.Timestamp"},
+		{protoType: ".google.protobuf.StringValue", want: "*wrapperspb.StringValue"},
+		{protoType: "google.type.Money", want: "*Money"},
+		{protoType: "acme.billing.invoice_status", want: "*InvoiceStatus"},
+		{protoType: "Invoice", want: "*Invoice"},
+		{protoType: "acme.billing.Invoice", kind: []string{"message"}, want: "*Invoice"},
+		{protoType: "acme.billing.InvoiceStatus", kind: []string{"enum"}, want: "InvoiceStatus"},
+		{protoType: ".acme.billing.Invoice.Item", want: "*Invoice_Item"},
+		{protoType: "acme.billing.Invoice.Item.Kind", kind: []string{"enum"}, want: "Invoice_Item_Kind"},
+	}
+
+	for _, tt := range tests {
+		if got := protoToGo(tt.protoType, tt.kind...); got != tt.want {
+			t.Errorf("protoToGo(%q, %v): got %q, want %q", tt.protoType, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestCaseHelpers(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(string) string
+		s    string
+		want string
+	}{
+		{name: "toCamel", fn: strcase.ToCamel, s: "invoice_item", want: "InvoiceItem"},
+		{name: "toSnake", fn: strcase.ToSnake, s: "InvoiceItem", want: "invoice_item"},
+		{name: "toUpperSnake", fn: strcase.ToScreamingSnake, s: "invoiceItem", want: "INVOICE_ITEM"},
+		{name: "toKebab", fn: strcase.ToKebab, s: "InvoiceItem", want: "invoice-item"},
+		{name: "title", fn: title, s: "invoice item_name-id", want: "Invoice Item_Name-Id"},
+		{name: "goIdent", fn: goIdent, s: "invoice-item", want: "invoice_item"},
+		{name: "goIdent", fn: goIdent, s: "1st", want: "_1st"},
+		{name: "goIdent", fn: goIdent, s: "type", want: "type_"},
+		{name: "goIdent", fn: goIdent, s: "", want: "_"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.fn(tt.s); got != tt.want {
+			t.Errorf("%s(%q): got %q, want %q", tt.name, tt.s, got, tt.want)
+		}
+	}
+}
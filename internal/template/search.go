@@ -0,0 +1,127 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mikros-dev/mikros-cli/internal/git"
+)
+
+const (
+	// OriginBuiltin is the origin of templates embedded in the CLI.
+	OriginBuiltin = "builtin"
+
+	// ProjectTemplatesDir is the directory, relative to the project root,
+	// where templates overriding the built-in ones can be placed.
+	ProjectTemplatesDir = ".mikros/templates"
+)
+
+// Set is a group of built-in templates that can be overridden by templates
+// from a directory with the same name inside a templates directory.
+type Set struct {
+	// Name is the set directory name inside a templates directory.
+	Name string
+
+	// Files holds the built-in templates.
+	Files fs.FS
+
+	// BasePath is the path of the templates inside Files.
+	BasePath string
+}
+
+// SearchPaths holds the templates directories where templates overriding the
+// built-in ones are searched, in order of precedence.
+type SearchPaths []string
+
+// NewSearchPaths creates the templates search paths using the profile
+// templates directory (if any) and the templates directory of the project of
+// the current directory. Since it depends on the current directory, it must
+// be called before moving to the destination path.
+func NewSearchPaths(templatesDir string) SearchPaths {
+	var paths SearchPaths
+
+	if templatesDir != "" {
+		paths = append(paths, os.ExpandEnv(templatesDir))
+	}
+
+	if root, err := projectRoot(); err == nil {
+		paths = append(paths, filepath.Join(root, ProjectTemplatesDir))
+	}
+
+	return paths
+}
+
+// Set returns the directories where the templates of the set named name are
+// searched.
+func (p SearchPaths) Set(name string) []string {
+	paths := make([]string, len(p))
+	for i, dir := range p {
+		paths[i] = filepath.Join(dir, name)
+	}
+
+	return paths
+}
+
+// projectRoot returns the root directory of the repository of the current
+// directory or, if there is no repository, the current directory itself.
+func projectRoot() (string, error) {
+	repo, err := git.LoadFromCwd()
+	if err == nil && repo.IsValidRepository() {
+		return repo.RootPath, nil
+	}
+
+	return os.Getwd()
+}
+
+// Export writes the built-in templates of the set inside a directory with the
+// set name in dir. Existing files are only replaced if overwrite is true. It
+// returns the paths of the written files.
+func (s Set) Export(dir string, overwrite bool) ([]string, error) {
+	entries, err := fs.ReadDir(s.Files, s.BasePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading files: %w", err)
+	}
+
+	var (
+		destination = filepath.Join(dir, s.Name)
+		names       []string
+	)
+
+	// Check everything before writing, so we don't leave a partially
+	// exported set behind.
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") {
+			continue
+		}
+
+		filename := filepath.Join(destination, entry.Name())
+		if _, err := os.Stat(filename); err == nil && !overwrite {
+			return nil, fmt.Errorf("file '%s' already exists", filename)
+		}
+		names = append(names, entry.Name())
+	}
+
+	if err := os.MkdirAll(destination, 0755); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, name := range names {
+		filename := filepath.Join(destination, name)
+		data, err := fs.ReadFile(s.Files, path.Join(s.BasePath, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading file: %w", err)
+		}
+
+		if err := os.WriteFile(filename, data, 0644); err != nil {
+			return nil, err
+		}
+		written = append(written, filename)
+	}
+
+	return written, nil
+}
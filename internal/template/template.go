@@ -3,27 +3,24 @@ package template
 import (
 	"bufio"
 	"bytes"
-	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"slices"
+	"strings"
+	"sync"
 	"text/template"
-
-	"github.com/iancoleman/strcase"
 )
 
-var defaultAPI = template.FuncMap{
-	"toCamel":      strcase.ToCamel,
-	"toSnake":      strcase.ToSnake,
-	"toUpperSnake": strcase.ToScreamingSnake,
-	"basename":     path.Base,
-	"toKebab":      strcase.ToKebab,
-}
-
 // Session is the template session.
 type Session struct {
 	loadedTemplates []*Info
+	workers         int
 }
 
 // Info is the template information.
@@ -31,6 +28,22 @@ type Info struct {
 	template *template.Template
 	name     File
 	context  interface{}
+	api      template.FuncMap
+	origin   string
+}
+
+// FileError is the error of a single template of a session.
+type FileError struct {
+	Template string
+	Err      error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("template '%s': %v", e.Template, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
 }
 
 // File is the representation of a template file to be processed when
@@ -41,7 +54,9 @@ type File struct {
 	Name string
 
 	// Output is an optional name that the template can have after it is
-	// processed (it replaces the Name member).
+	// processed (it replaces the Name member). It can be a path, relative to
+	// the destination directory, and use template expressions with the same
+	// context of the template.
 	Output string
 
 	// Extension is an optional field to set the file extension.
@@ -53,23 +68,49 @@ type LoadOptions struct {
 	TemplatesToUse []File
 	API            map[string]interface{}
 	FilesBasePath  string
+
+	// SearchPaths holds directories where templates are searched, in order,
+	// before using the ones from the files. Templates found there replace
+	// the ones with the same name, and new ones are added to the session.
+	SearchPaths []string
+
+	// Workers sets how many templates are executed in parallel. When zero,
+	// the number of available CPUs is used.
+	Workers int
+
+	// Origin describes where the templates of sessions created from data
+	// come from, like a plugin.
+	Origin string
 }
 
 // NewSessionFromFiles creates a new template session from a set of files.
-func NewSessionFromFiles(options *LoadOptions, files embed.FS) (*Session, error) {
-	dirFiles, err := files.ReadDir(options.FilesBasePath)
+func NewSessionFromFiles(options *LoadOptions, files fs.FS) (*Session, error) {
+	dirFiles, err := fs.ReadDir(files, options.FilesBasePath)
 	if err != nil {
 		return nil, fmt.Errorf("reading files: %w", err)
 	}
 
+	overrides, err := loadSearchPathsTemplates(options.SearchPaths)
+	if err != nil {
+		return nil, err
+	}
+
 	var templates []*Info
 	for _, file := range dirFiles {
-		data, err := files.ReadFile(filepath.Join(options.FilesBasePath, file.Name()))
+		data, err := fs.ReadFile(files, path.Join(options.FilesBasePath, file.Name()))
 		if err != nil {
 			return nil, fmt.Errorf("reading file: %w", err)
 		}
 
-		var name = filenameWithoutExtension(file.Name())
+		var (
+			name   = filenameWithoutExtension(file.Name())
+			origin = OriginBuiltin
+		)
+		if override, ok := overrides[name]; ok {
+			data = override.data
+			origin = override.path
+			delete(overrides, name)
+		}
 
 		idx := slices.IndexFunc(options.TemplatesToUse, func(t File) bool {
 			return t.Name == name
@@ -79,20 +120,79 @@ func NewSessionFromFiles(options *LoadOptions, files embed.FS) (*Session, error)
 			continue
 		}
 
-		tpl, err := loadTemplate(name, data, options)
+		info, err := loadTemplate(name, data, options.TemplatesToUse[idx], options)
 		if err != nil {
 			return nil, err
 		}
+		info.origin = origin
 
-		templates = append(templates, &Info{
-			name:     options.TemplatesToUse[idx],
-			template: tpl,
-		})
+		templates = append(templates, info)
 	}
 
-	return &Session{
-		loadedTemplates: templates,
-	}, nil
+	// Templates that only exist in the search paths extend the session,
+	// keeping their names as output.
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		info, err := loadTemplate(name, overrides[name].data, File{Name: name}, options)
+		if err != nil {
+			return nil, err
+		}
+		info.origin = overrides[name].path
+
+		templates = append(templates, info)
+	}
+
+	return newSession(templates, options), nil
+}
+
+type searchPathTemplate struct {
+	path string
+	data []byte
+}
+
+// loadSearchPathsTemplates reads all templates from paths, indexed by their
+// names. Templates from the first paths take precedence.
+func loadSearchPathsTemplates(paths []string) (map[string]*searchPathTemplate, error) {
+	templates := make(map[string]*searchPathTemplate)
+
+	for _, p := range paths {
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("reading templates directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") {
+				continue
+			}
+
+			name := filenameWithoutExtension(entry.Name())
+			if _, ok := templates[name]; ok {
+				continue
+			}
+
+			filename := filepath.Join(p, entry.Name())
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, fmt.Errorf("reading file: %w", err)
+			}
+			templates[name] = &searchPathTemplate{
+				path: filename,
+				data: data,
+			}
+		}
+	}
+
+	return templates, nil
 }
 
 // Data is the template data.
@@ -103,61 +203,72 @@ type Data struct {
 }
 
 // NewSessionFromData creates a new template session from a set of data.
+// Every file is loaded with the entry of options.TemplatesToUse at the same
+// index, so the same data can be used more than once with different outputs.
 func NewSessionFromData(options *LoadOptions, files []*Data) (*Session, error) {
-	var templates []*Info
-	for _, file := range files {
-		var name = filenameWithoutExtension(file.FileName)
-
-		idx := slices.IndexFunc(options.TemplatesToUse, func(t File) bool {
-			tplName := t.Name
-			if tplName == "" {
-				tplName = t.Output
-			}
-
-			return tplName == name
-		})
-		if idx == -1 {
-			// The template is not being used at the moment.
-			continue
-		}
+	if len(files) != len(options.TemplatesToUse) {
+		return nil, fmt.Errorf("got %d templates for %d files", len(options.TemplatesToUse), len(files))
+	}
 
-		tpl, err := loadTemplate(name, file.Content, options)
+	templates := make([]*Info, len(files))
+	for i, file := range files {
+		info, err := loadTemplate(filenameWithoutExtension(file.FileName), file.Content, options.TemplatesToUse[i], options)
 		if err != nil {
 			return nil, err
 		}
+		info.context = file.Context
+		info.origin = options.Origin
 
-		templates = append(templates, &Info{
-			name:     options.TemplatesToUse[idx],
-			template: tpl,
-			context:  file.Context,
-		})
+		templates[i] = info
+	}
+
+	return newSession(templates, options), nil
+}
+
+func newSession(templates []*Info, options *LoadOptions) *Session {
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
 	}
 
 	return &Session{
 		loadedTemplates: templates,
-	}, nil
+		workers:         workers,
+	}
 }
 
 func filenameWithoutExtension(filename string) string {
 	return filename[:len(filename)-len(filepath.Ext(filename))]
 }
 
-func loadTemplate(name string, data []byte, options *LoadOptions) (*template.Template, error) {
-	var helperAPI = defaultAPI
-
+func loadTemplate(name string, data []byte, file File, options *LoadOptions) (*Info, error) {
+	// Every template has its own functions, since templateName depends on it.
+	helperAPI := newHelperAPI(options.API)
 	helperAPI["templateName"] = func() string {
 		return name
 	}
-	for call, function := range options.API {
-		helperAPI[call] = function
-	}
 
 	tpl, err := parse(name, data, helperAPI)
 	if err != nil {
-		return nil, err
+		return nil, &FileError{Template: name, Err: err}
 	}
 
-	return tpl, nil
+	return &Info{
+		name:     file,
+		template: tpl,
+		api:      helperAPI,
+	}, nil
+}
+
+// newHelperAPI creates a copy of the default template functions with the
+// custom ones added.
+func newHelperAPI(api map[string]interface{}) template.FuncMap {
+	helperAPI := maps.Clone(defaultAPI)
+	for call, function := range api {
+		helperAPI[call] = function
+	}
+
+	return helperAPI
 }
 
 func parse(key string, data []byte, helperAPI template.FuncMap) (*template.Template, error) {
@@ -169,49 +280,106 @@ func parse(key string, data []byte, helperAPI template.FuncMap) (*template.Templ
 	return t, nil
 }
 
-// ExecuteTemplates executes the templates in the session.
+// ExecuteTemplates executes the templates in the session, in parallel. The
+// generated templates keep the session order. When templates fail, the
+// returned error joins a FileError for each one of them.
 func (s *Session) ExecuteTemplates(ctx interface{}) ([]*GeneratedTemplate, error) {
-	var gen []*GeneratedTemplate
+	var (
+		gen  = make([]*GeneratedTemplate, len(s.loadedTemplates))
+		errs = make([]error, len(s.loadedTemplates))
+		jobs = make(chan int)
+		wg   sync.WaitGroup
+	)
+
+	for range min(s.workers, len(s.loadedTemplates)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				gen[i], errs[i] = s.loadedTemplates[i].execute(ctx)
+			}
+		}()
+	}
 
-	for _, t := range s.loadedTemplates {
-		var buf bytes.Buffer
-		w := bufio.NewWriter(&buf)
+	for i := range s.loadedTemplates {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-		tplCtx := ctx
-		if ctx == nil {
-			tplCtx = t.context
-		}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
 
-		if err := t.template.Execute(w, tplCtx); err != nil {
-			return nil, err
-		}
+	return gen, nil
+}
 
-		_ = w.Flush()
-		gen = append(gen, newGeneratedTemplate(&buf, t.name))
+func (i *Info) execute(ctx interface{}) (*GeneratedTemplate, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	tplCtx := ctx
+	if ctx == nil {
+		tplCtx = i.context
 	}
 
-	return gen, nil
+	if err := i.template.Execute(w, tplCtx); err != nil {
+		return nil, &FileError{Template: i.template.Name(), Err: err}
+	}
+
+	_ = w.Flush()
+	filename, err := i.outputFilename(tplCtx)
+	if err != nil {
+		return nil, &FileError{Template: i.template.Name(), Err: err}
+	}
+
+	content, err := formatSource(filename, buf.Bytes())
+	if err != nil {
+		return nil, &FileError{Template: i.template.Name(), Err: err}
+	}
+
+	return &GeneratedTemplate{
+		data:     bytes.NewBuffer(content),
+		name:     filename,
+		template: i.template.Name(),
+		origin:   i.origin,
+	}, nil
 }
 
 // GeneratedTemplate is the generated template.
 type GeneratedTemplate struct {
-	data *bytes.Buffer
-	name string
+	data     *bytes.Buffer
+	name     string
+	template string
+	origin   string
 }
 
-func newGeneratedTemplate(data *bytes.Buffer, name File) *GeneratedTemplate {
-	filename := name.Name
-	if name.Output != "" {
-		filename = name.Output
+// outputFilename builds the path of the file generated by the template. It
+// may have directories and template expressions, but it must remain inside
+// the destination directory.
+func (i *Info) outputFilename(ctx interface{}) (string, error) {
+	filename := i.name.Name
+	if i.name.Output != "" {
+		filename = i.name.Output
 	}
-	if name.Extension != "" {
-		filename += fmt.Sprintf(".%v", name.Extension)
+	if i.name.Extension != "" {
+		filename += fmt.Sprintf(".%v", i.name.Extension)
 	}
 
-	return &GeneratedTemplate{
-		data: data,
-		name: filename,
+	if strings.Contains(filename, "{{") {
+		f, err := parseBlock(filename, i.api, ctx)
+		if err != nil {
+			return "", fmt.Errorf("could not build output path: %w", err)
+		}
+		filename = f
 	}
+
+	filename = filepath.Clean(filepath.FromSlash(filename))
+	if !filepath.IsLocal(filename) {
+		return "", fmt.Errorf("output path '%s' must be relative to the destination directory", filename)
+	}
+
+	return filename, nil
 }
 
 // Filename returns the generated template filename.
@@ -223,3 +391,26 @@ func (g *GeneratedTemplate) Filename() string {
 func (g *GeneratedTemplate) Content() []byte {
 	return g.data.Bytes()
 }
+
+// TemplateName returns the name of the template that generated the content.
+func (g *GeneratedTemplate) TemplateName() string {
+	return g.template
+}
+
+// Origin returns where the template came from: OriginBuiltin for built-in
+// templates, the template path for templates found in search paths, or the
+// session origin for templates loaded from data.
+func (g *GeneratedTemplate) Origin() string {
+	return g.origin
+}
+
+// Write writes the generated template content into its file inside basePath,
+// creating its directories when needed.
+func (g *GeneratedTemplate) Write(basePath string) error {
+	filename := filepath.Join(basePath, g.name)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, g.Content(), 0644)
+}
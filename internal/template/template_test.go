@@ -0,0 +1,106 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// newTestSession creates a session with a template for each content, named
+// by its index.
+func newTestSession(t *testing.T, workers int, contents ...string) *Session {
+	t.Helper()
+
+	var (
+		files     []*Data
+		templates []File
+	)
+	for i, content := range contents {
+		name := fmt.Sprintf("file%02d", i)
+		files = append(files, &Data{
+			FileName: name + ".tmpl",
+			Content:  []byte(content),
+		})
+		templates = append(templates, File{Name: name, Extension: "txt"})
+	}
+
+	session, err := NewSessionFromData(&LoadOptions{
+		TemplatesToUse: templates,
+		Workers:        workers,
+	}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return session
+}
+
+func TestExecuteTemplatesOrder(t *testing.T) {
+	var contents []string
+	for i := range 50 {
+		contents = append(contents, fmt.Sprintf("{{ .Name | pluralize }} %d", i))
+	}
+
+	for _, workers := range []int{1, 4, 100} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			session := newTestSession(t, workers, contents...)
+
+			gen, err := session.ExecuteTemplates(map[string]string{"Name": "invoice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(gen) != len(contents) {
+				t.Fatalf("got %d generated templates, want %d", len(gen), len(contents))
+			}
+
+			for i, g := range gen {
+				var (
+					wantName    = fmt.Sprintf("file%02d.txt", i)
+					wantContent = fmt.Sprintf("invoices %d", i)
+				)
+				if g.Filename() != wantName || string(g.Content()) != wantContent {
+					t.Errorf("template %d: got %s %q, want %s %q", i, g.Filename(), g.Content(), wantName, wantContent)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteTemplatesErrors(t *testing.T) {
+	session := newTestSession(t, 4,
+		"ok",
+		`{{ dict "key" }}`,
+		"ok",
+		`{{ template "missing" }}`,
+		"ok",
+	)
+
+	gen, err := session.ExecuteTemplates(map[string]interface{}{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if gen != nil {
+		t.Errorf("got generated templates %v, want none", gen)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("got error %T, want joined errors", err)
+	}
+
+	var failed []string
+	for _, e := range joined.Unwrap() {
+		var fileErr *FileError
+		if !errors.As(e, &fileErr) {
+			t.Fatalf("got error %T, want *FileError", e)
+		}
+		failed = append(failed, fileErr.Template)
+	}
+	if got, want := strings.Join(failed, ","), "file01,file03"; got != want {
+		t.Errorf("got failed templates %s, want %s", got, want)
+	}
+	if !strings.Contains(err.Error(), "template 'file01': ") || !strings.Contains(err.Error(), "dict expects pairs of keys and values") {
+		t.Errorf("got error %q", err)
+	}
+}
@@ -16,10 +16,18 @@ import (
 type FormOptions struct {
 	Theme      *huh.Theme
 	Accessible bool
+
+	// Defaults holds values, indexed by question name, that replace the
+	// questions default values when the form is presented.
+	Defaults map[string]interface{}
 }
 
 // RunFormFromSurvey executes a survey form and returns the collected data as a map.
 func RunFormFromSurvey(name string, s *survey.Survey, options *FormOptions) (map[string]interface{}, error) {
+	if err := RequireInputs(SurveyInputs(name, s)...); err != nil {
+		return nil, err
+	}
+
 	if SurveyNeedsConfirmation(s) {
 		return runFormWithConfirmation(name, s, options)
 	}
@@ -71,7 +79,7 @@ func runFormWithConfirmation(name string, s *survey.Survey, options *FormOptions
 func runFormSurvey(name string, s *survey.Survey, options *FormOptions) (map[string]interface{}, error) {
 	values := make(map[string]interface{})
 
-	elements, err := buildFormSurveyElements(name, s, values)
+	elements, err := buildFormSurveyElements(name, s, values, options.Defaults)
 	if err != nil {
 		return nil, err
 	}
@@ -98,12 +106,34 @@ func runFormSurvey(name string, s *survey.Survey, options *FormOptions) (map[str
 	return results, nil
 }
 
-func buildFormSurveyElements(name string, s *survey.Survey, values map[string]interface{}) ([]huh.Field, error) {
+// SurveyInputs returns the inputs that a survey, its confirmation and its
+// follow-up surveys may ask for.
+func SurveyInputs(name string, s *survey.Survey) []Input {
+	var inputs []Input
+	if SurveyNeedsConfirmation(s) {
+		inputs = append(inputs, Input{Name: name + " confirmation"})
+	}
+	for _, q := range s.Questions {
+		inputs = append(inputs, Input{Name: fmt.Sprintf("%s.%s", name, q.Name)})
+	}
+	for _, f := range s.FollowUp {
+		inputs = append(inputs, SurveyInputs(f.Name, f.Survey)...)
+	}
+
+	return inputs
+}
+
+func buildFormSurveyElements(
+	name string,
+	s *survey.Survey,
+	values map[string]interface{},
+	defaults map[string]interface{},
+) ([]huh.Field, error) {
 	elements := make([]huh.Field, 0, len(s.Questions))
 
 	for _, q := range s.Questions {
 		title := fmt.Sprintf("[%s] %s", name, q.Message)
-		field, err := buildFormElementQuestion(q, title, values)
+		field, err := buildFormElementQuestion(q, title, values, defaults[q.Name])
 		if err != nil {
 			return nil, err
 		}
@@ -113,29 +143,39 @@ func buildFormSurveyElements(name string, s *survey.Survey, values map[string]in
 	return elements, nil
 }
 
-func buildFormElementQuestion(q *survey.Question, title string, values map[string]interface{}) (huh.Field, error) {
+func buildFormElementQuestion(
+	q *survey.Question,
+	title string,
+	values map[string]interface{},
+	defaultValue interface{},
+) (huh.Field, error) {
 	switch q.Prompt {
 	case survey.PromptInput:
-		return buildPromptInputQuestion(q, title, values), nil
+		return buildPromptInputQuestion(q, title, values, defaultValue), nil
 
 	case survey.PromptSelect:
-		return buildPromptSelectQuestion(q, title, values), nil
+		return buildPromptSelectQuestion(q, title, values, defaultValue), nil
 
 	case survey.PromptMultiSelect:
-		return buildPromptMultiSelectQuestion(q, title, values)
+		return buildPromptMultiSelectQuestion(q, title, values, defaultValue)
 
 	case survey.PromptMultiline:
-		return buildPromptMultilineQuestion(q, title, values), nil
+		return buildPromptMultilineQuestion(q, title, values, defaultValue), nil
 
 	case survey.PromptConfirm:
-		return buildPromptConfirmQuestion(q, title, values), nil
+		return buildPromptConfirmQuestion(q, title, values, defaultValue), nil
 	}
 
 	return nil, errors.New("unsupported prompt type")
 }
 
-func buildPromptInputQuestion(q *survey.Question, title string, values map[string]interface{}) huh.Field {
-	defaultValue := q.Default
+func buildPromptInputQuestion(
+	q *survey.Question,
+	title string,
+	values map[string]interface{},
+	previous interface{},
+) huh.Field {
+	defaultValue := stringDefault(q.Default, previous)
 	values[q.Name] = &defaultValue
 
 	input := huh.NewInput().Title(title).Value(values[q.Name].(*string))
@@ -146,11 +186,20 @@ func buildPromptInputQuestion(q *survey.Question, title string, values map[strin
 	return input
 }
 
-func buildPromptSelectQuestion(q *survey.Question, title string, values map[string]interface{}) huh.Field {
-	options := make([]huh.Option[string], len(q.Options))
+func buildPromptSelectQuestion(
+	q *survey.Question,
+	title string,
+	values map[string]interface{},
+	previous interface{},
+) huh.Field {
+	var (
+		defaultValue = stringDefault(q.Default, previous)
+		options      = make([]huh.Option[string], len(q.Options))
+	)
+
 	for i, option := range q.Options {
 		opt := huh.NewOption(option, option)
-		if option == q.Default {
+		if option == defaultValue {
 			opt = opt.Selected(true)
 		}
 		options[i] = opt
@@ -167,13 +216,15 @@ func buildPromptMultiSelectQuestion(
 	q *survey.Question,
 	title string,
 	values map[string]interface{},
+	previous interface{},
 ) (huh.Field, error) {
 	options := make([]huh.Option[string], len(q.Options))
 	for i, option := range q.Options {
 		options[i] = huh.NewOption(option, option)
 	}
 
-	values[q.Name] = new([]string)
+	defaultValue := stringSliceDefault(previous)
+	values[q.Name] = &defaultValue
 	prompt := huh.NewMultiSelect[string]().
 		Title(title).Options(options...).
 		Value(values[q.Name].(*[]string))
@@ -191,8 +242,14 @@ func buildPromptMultiSelectQuestion(
 	return prompt, nil
 }
 
-func buildPromptMultilineQuestion(q *survey.Question, title string, values map[string]interface{}) huh.Field {
-	values[q.Name] = new(string)
+func buildPromptMultilineQuestion(
+	q *survey.Question,
+	title string,
+	values map[string]interface{},
+	previous interface{},
+) huh.Field {
+	defaultValue := stringDefault(q.Default, previous)
+	values[q.Name] = &defaultValue
 	text := huh.NewText().Title(title).Value(values[q.Name].(*string))
 	if q.Required {
 		text = text.Validate(IsEmpty("cannot be empty"))
@@ -201,13 +258,21 @@ func buildPromptMultilineQuestion(q *survey.Question, title string, values map[s
 	return text
 }
 
-func buildPromptConfirmQuestion(q *survey.Question, title string, values map[string]interface{}) huh.Field {
+func buildPromptConfirmQuestion(
+	q *survey.Question,
+	title string,
+	values map[string]interface{},
+	previous interface{},
+) huh.Field {
 	defaultValue := false
 	if q.Default != "" {
 		if b, err := strconv.ParseBool(q.Default); err == nil {
 			defaultValue = b
 		}
 	}
+	if b, ok := previous.(bool); ok {
+		defaultValue = b
+	}
 
 	values[q.Name] = &defaultValue
 	return huh.NewConfirm().
@@ -215,6 +280,37 @@ func buildPromptConfirmQuestion(q *survey.Question, title string, values map[str
 		Value(values[q.Name].(*bool))
 }
 
+// stringDefault returns the previous answer of a question, if it has one, or
+// its default value.
+func stringDefault(defaultValue string, previous interface{}) string {
+	if s, ok := previous.(string); ok && s != "" {
+		return s
+	}
+
+	return defaultValue
+}
+
+// stringSliceDefault converts the previous answer of a question into a slice
+// of strings. Answers loaded from files hold their items as interface{}.
+func stringSliceDefault(previous interface{}) []string {
+	switch v := previous.(type) {
+	case []string:
+		return slices.Clone(v)
+
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
+
 func extractResults(values map[string]interface{}) map[string]interface{} {
 	results := make(map[string]interface{})
 
@@ -313,7 +409,7 @@ func yesNo(message, defaultValue string) (bool, error) {
 		),
 	)
 
-	if err := f.Run(); err != nil {
+	if err := RunForm(f, message); err != nil {
 		return false, err
 	}
 
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/mattn/go-isatty"
+)
+
+// Input is something that a form asks for.
+type Input struct {
+	Name string
+
+	// Source is the flag or file that provides the input without asking
+	// it, if any.
+	Source string
+}
+
+func (i Input) String() string {
+	if i.Source == "" {
+		return i.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", i.Name, i.Source)
+}
+
+// NewInputs creates inputs, without sources, with the given names.
+func NewInputs(names ...string) []Input {
+	inputs := make([]Input, len(names))
+	for i, name := range names {
+		inputs[i] = Input{Name: name}
+	}
+
+	return inputs
+}
+
+// MissingInputsError is returned when a form needs to be answered but there
+// is no terminal available to present it.
+type MissingInputsError struct {
+	Inputs []Input
+}
+
+func (e *MissingInputsError) Error() string {
+	inputs := make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		inputs[i] = input.String()
+	}
+
+	return fmt.Sprintf("not running in an interactive terminal, missing inputs: %s", strings.Join(inputs, ", "))
+}
+
+// IsInteractive checks if both the standard input and output are attached to
+// a terminal, i.e., if forms can be presented to the user.
+func IsInteractive() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// RequireInputs checks, before presenting any form, if the inputs asked by
+// all forms of a command can be answered. When not running inside an
+// interactive terminal, it fails with a MissingInputsError describing all of
+// them, instead of only the ones of the first form.
+func RequireInputs(inputs ...Input) error {
+	if !IsInteractive() {
+		return &MissingInputsError{
+			Inputs: inputs,
+		}
+	}
+
+	return nil
+}
+
+// RunForm executes the form when running inside an interactive terminal.
+// Otherwise, it fails right away with a MissingInputsError describing the
+// inputs that the form would ask for.
+func RunForm(form *huh.Form, inputs ...string) error {
+	if err := RequireInputs(NewInputs(inputs...)...); err != nil {
+		return err
+	}
+
+	return form.Run()
+}
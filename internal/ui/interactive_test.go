@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/plugin/survey"
+)
+
+func TestRequireInputs(t *testing.T) {
+	if IsInteractive() {
+		t.Skip("running in an interactive terminal")
+	}
+
+	s := &survey.Survey{
+		Questions: []*survey.Question{{Name: "host"}, {Name: "port"}},
+		FollowUp: []*survey.FollowUpSurvey{
+			{
+				Name:   "tls",
+				Survey: &survey.Survey{Questions: []*survey.Question{{Name: "cert"}}},
+			},
+		},
+	}
+	inputs := append([]Input{{Name: "project kind", Source: "kind argument"}}, SurveyInputs("db", s)...)
+
+	err := RequireInputs(inputs...)
+	var missing *MissingInputsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("got error %v, want *MissingInputsError", err)
+	}
+
+	want := "not running in an interactive terminal, missing inputs: project kind (kind argument), db.host, db.port, tls.cert"
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+}
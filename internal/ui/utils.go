@@ -1,15 +1,23 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/charmbracelet/huh"
 
 	"github.com/mikros-dev/mikros-cli/internal/settings"
 )
 
-// Alert displays a confirmation dialog with the provided text.
+// Alert displays a confirmation dialog with the provided text. When not
+// running inside an interactive terminal, the text is only printed.
 func Alert(cfg *settings.Settings, text string) error {
+	if !IsInteractive() {
+		_, err := fmt.Fprintln(os.Stderr, text)
+		return err
+	}
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
@@ -35,3 +43,11 @@ func Message(cfg *settings.Settings, title, text string) {
 	_ = n.Init()
 	fmt.Println(n.View())
 }
+
+// JSON writes v into the standard output as an indented JSON document.
+func JSON(v interface{}) error {
+	en := json.NewEncoder(os.Stdout)
+	en.SetIndent("", "  ")
+
+	return en.Encode(v)
+}
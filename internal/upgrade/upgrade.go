@@ -0,0 +1,306 @@
+package upgrade
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mikros-dev/mikros-cli/internal/git"
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+)
+
+// Status is what happened to a file during an upgrade.
+type Status string
+
+const (
+	// StatusUnchanged means the file already has the regenerated content,
+	// or only the user changed it.
+	StatusUnchanged Status = "unchanged"
+
+	// StatusUpdated means the file was not changed by the user and was
+	// replaced by the regenerated content.
+	StatusUpdated Status = "updated"
+
+	// StatusMerged means changes from both the user and the regenerated
+	// content were merged without conflicts.
+	StatusMerged Status = "merged"
+
+	// StatusConflict means the file has conflict markers to be resolved.
+	StatusConflict Status = "conflict"
+
+	// StatusAdded means the file is new in the regenerated project.
+	StatusAdded Status = "added"
+
+	// StatusDeleted means the user deleted the file, so it was not
+	// generated again.
+	StatusDeleted Status = "deleted"
+
+	// StatusObsolete means the file is not generated anymore. It is kept
+	// untouched.
+	StatusObsolete Status = "obsolete"
+)
+
+var conflictLabels = [3]string{"current", "previous generation", "new generation"}
+
+// Options holds options for merging an upgrade.
+type Options struct {
+	// DryRun only reports what would happen, without changing any file.
+	DryRun bool
+
+	// Files, when set, limits the upgrade to these files, relative to the
+	// project directory. The manifest entries of other files are kept as
+	// they were.
+	Files []string
+}
+
+func (o *Options) selected(path string) bool {
+	return len(o.Files) == 0 || slices.Contains(o.Files, path)
+}
+
+// Result is the result of an upgrade.
+type Result struct {
+	Path  string        `json:"path"`
+	Files []*FileResult `json:"files"`
+}
+
+// FileResult is what happened to a single file.
+type FileResult struct {
+	Path   string `json:"path"`
+	Status Status `json:"status"`
+}
+
+// Conflicts returns the files left with conflicts.
+func (r *Result) Conflicts() []string {
+	var files []string
+	for _, f := range r.Files {
+		if f.Status == StatusConflict {
+			files = append(files, f.Path)
+		}
+	}
+
+	return files
+}
+
+// Merge merges a project regenerated at generatedPath into the project at
+// projectPath, whose previous generation is described by previous. Files
+// changed by users are three-way merged, using the previous generated
+// content as base, leaving conflict markers where both changed the same
+// region. The project manifest is replaced by the one of the regenerated
+// project, or only updated for the selected files when options.Files is
+// set.
+func Merge(projectPath string, previous *manifest.Manifest, generatedPath string, options *Options) (*Result, error) {
+	generated, err := manifest.Load(generatedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Path: projectPath,
+	}
+
+	for _, f := range generated.Files {
+		if !options.selected(f.Path) {
+			continue
+		}
+
+		filename := filepath.Join(generatedPath, filepath.FromSlash(f.Path))
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		status, merged, err := mergeFile(projectPath, previous, f.Path, content)
+		if err != nil {
+			return nil, err
+		}
+
+		if !options.DryRun && merged != nil {
+			if err := writeFile(filepath.Join(projectPath, filepath.FromSlash(f.Path)), merged, info.Mode()); err != nil {
+				return nil, err
+			}
+		}
+
+		result.Files = append(result.Files, &FileResult{
+			Path:   f.Path,
+			Status: status,
+		})
+	}
+
+	for _, f := range previous.Files {
+		if !options.selected(f.Path) {
+			continue
+		}
+		if _, ok := generated.File(f.Path); !ok {
+			result.Files = append(result.Files, &FileResult{
+				Path:   f.Path,
+				Status: StatusObsolete,
+			})
+		}
+	}
+
+	if !options.DryRun {
+		if len(options.Files) > 0 {
+			err = updateManifest(projectPath, previous, generatedPath, generated, options.Files)
+		} else {
+			err = replaceManifest(projectPath, generatedPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// mergeFile decides what happens to a file. It returns the content to be
+// written, or nil if the file must be left untouched.
+func mergeFile(projectPath string, previous *manifest.Manifest, path string, generated []byte) (Status, []byte, error) {
+	prev, wasGenerated := previous.File(path)
+
+	current, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(path)))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", nil, err
+		}
+		if wasGenerated {
+			return StatusDeleted, nil, nil
+		}
+
+		return StatusAdded, generated, nil
+	}
+
+	if bytes.Equal(current, generated) {
+		return StatusUnchanged, nil, nil
+	}
+
+	var base []byte
+	if wasGenerated {
+		if manifest.Hash(current) == prev.Hash {
+			// Not changed by the user.
+			return StatusUpdated, generated, nil
+		}
+
+		if b, ok := prev.Base(projectPath); ok {
+			base = b
+		}
+	}
+
+	if base != nil && bytes.Equal(base, generated) {
+		// Only the user changed it.
+		return StatusUnchanged, nil, nil
+	}
+
+	// Without the previous generated content, everything that differs
+	// becomes a conflict.
+	merged, conflicts, err := git.MergeFile(current, base, generated, conflictLabels)
+	if err != nil {
+		return "", nil, err
+	}
+	if conflicts > 0 {
+		return StatusConflict, merged, nil
+	}
+
+	return StatusMerged, merged, nil
+}
+
+// writeFile writes content into filename, keeping its mode if it already
+// exists.
+func writeFile(filename string, content []byte, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode()
+	}
+
+	return os.WriteFile(filename, content, mode)
+}
+
+// replaceManifest copies the manifest of the regenerated project, and its
+// generated content, into the project.
+func replaceManifest(projectPath, generatedPath string) error {
+	if err := os.RemoveAll(filepath.Join(projectPath, manifest.BaseDir)); err != nil {
+		return err
+	}
+
+	files := []string{manifest.Filename}
+	err := filepath.WalkDir(filepath.Join(generatedPath, manifest.BaseDir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(generatedPath, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(generatedPath, name))
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(projectPath, name), data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateManifest updates the entries of the project manifest, and their
+// generated content, for the given files only, taking them from the
+// regenerated project.
+func updateManifest(projectPath string, previous *manifest.Manifest, generatedPath string, generated *manifest.Manifest, files []string) error {
+	m := *previous
+	m.Files = slices.DeleteFunc(slices.Clone(previous.Files), func(f *manifest.File) bool {
+		return slices.Contains(files, f.Path)
+	})
+
+	for _, path := range files {
+		base := filepath.Join(projectPath, manifest.BaseDir, filepath.FromSlash(path))
+		if err := os.Remove(base); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		f, ok := generated.File(path)
+		if !ok {
+			continue
+		}
+		m.Files = append(m.Files, f)
+
+		data, err := os.ReadFile(filepath.Join(generatedPath, manifest.BaseDir, filepath.FromSlash(path)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+		if err := writeFile(base, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return m.Save(projectPath)
+}
@@ -0,0 +1,320 @@
+package upgrade
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/manifest"
+)
+
+// previousFiles are the files of the previous generation of the test
+// project.
+var previousFiles = map[string]string{
+	"unchanged.txt": "same\n",
+	"updated.txt":   "old\n",
+	"user.txt":      "generated\n",
+	"merged.txt":    "1\n2\n3\n4\n5\n",
+	"conflict.txt":  "1\n2\n3\n",
+	"deleted.txt":   "deleted\n",
+	"obsolete.txt":  "obsolete\n",
+}
+
+// userFiles are the changes made by the user after the previous generation.
+// Empty contents mean deleted files.
+var userFiles = map[string]string{
+	"user.txt":     "edited\n",
+	"merged.txt":   "one\n2\n3\n4\n5\n",
+	"conflict.txt": "1\nuser\n3\n",
+	"deleted.txt":  "",
+}
+
+// regeneratedFiles are the files of the new generation of the test project.
+var regeneratedFiles = map[string]string{
+	"unchanged.txt": "same\n",
+	"updated.txt":   "new\n",
+	"user.txt":      "generated\n",
+	"merged.txt":    "1\n2\n3\n4\nfive\n",
+	"conflict.txt":  "1\ngenerated\n3\n",
+	"deleted.txt":   "deleted\n",
+	"dir/added.txt": "added\n",
+}
+
+// generate writes files, and their manifest, as a project generated at dir.
+func generate(t *testing.T, dir string, files map[string]string) *manifest.Manifest {
+	t.Helper()
+
+	m := manifest.New("test")
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		m.AddFile(filename, manifest.OriginDefinitions, []byte(content))
+	}
+	if err := m.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+// setupProject creates the test project, changed by the user, and its
+// regeneration, returning their paths and the previous manifest.
+func setupProject(t *testing.T) (string, string, *manifest.Manifest) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	var (
+		project     = t.TempDir()
+		regenerated = t.TempDir()
+	)
+
+	previous := generate(t, project, previousFiles)
+	for name, content := range userFiles {
+		filename := filepath.Join(project, name)
+		if content == "" {
+			if err := os.Remove(filename); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	generate(t, regenerated, regeneratedFiles)
+
+	return project, regenerated, previous
+}
+
+// readTree returns the content of every file below dir.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+func statuses(result *Result) map[string]Status {
+	s := make(map[string]Status)
+	for _, f := range result.Files {
+		s[f.Path] = f.Status
+	}
+
+	return s
+}
+
+func TestMerge(t *testing.T) {
+	project, regenerated, previous := setupProject(t)
+
+	result, err := Merge(project, previous, regenerated, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantStatuses := map[string]Status{
+		"unchanged.txt": StatusUnchanged,
+		"updated.txt":   StatusUpdated,
+		"user.txt":      StatusUnchanged,
+		"merged.txt":    StatusMerged,
+		"conflict.txt":  StatusConflict,
+		"deleted.txt":   StatusDeleted,
+		"obsolete.txt":  StatusObsolete,
+		"dir/added.txt": StatusAdded,
+	}
+	if got := statuses(result); !reflect.DeepEqual(got, wantStatuses) {
+		t.Errorf("got statuses %v, want %v", got, wantStatuses)
+	}
+	if got := result.Conflicts(); !reflect.DeepEqual(got, []string{"conflict.txt"}) {
+		t.Errorf("got conflicts %v", got)
+	}
+
+	tree := readTree(t, project)
+	wantContents := map[string]string{
+		"unchanged.txt": "same\n",
+		"updated.txt":   "new\n",
+		"user.txt":      "edited\n",
+		"merged.txt":    "one\n2\n3\n4\nfive\n",
+		"obsolete.txt":  "obsolete\n",
+		"dir/added.txt": "added\n",
+	}
+	for name, want := range wantContents {
+		if got := tree[name]; got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+	if _, ok := tree["deleted.txt"]; ok {
+		t.Error("deleted.txt: generated again")
+	}
+
+	wantConflict := "1\n<<<<<<< current\nuser\n=======\ngenerated\n>>>>>>> new generation\n3\n"
+	if got := tree["conflict.txt"]; got != wantConflict {
+		t.Errorf("conflict.txt: got %q, want %q", got, wantConflict)
+	}
+
+	// The project gets the manifest and base of the regeneration.
+	m, err := manifest.Load(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.File("obsolete.txt"); ok {
+		t.Error("manifest: obsolete.txt still recorded")
+	}
+	f, ok := m.File("dir/added.txt")
+	if !ok {
+		t.Fatal("manifest: dir/added.txt not recorded")
+	}
+	if base, ok := f.Base(project); !ok || string(base) != "added\n" {
+		t.Errorf("base of dir/added.txt: got %q (%v)", base, ok)
+	}
+	if _, ok := tree[manifest.BaseDir+"/obsolete.txt"]; ok {
+		t.Error("base of obsolete.txt kept")
+	}
+}
+
+func TestMergeWithoutBase(t *testing.T) {
+	project, regenerated, previous := setupProject(t)
+	if err := os.RemoveAll(filepath.Join(project, manifest.BaseDir)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Merge(project, previous, regenerated, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files not changed by the user are still recognized by their hashes,
+	// while changed ones can't be merged without their base.
+	got := statuses(result)
+	want := map[string]Status{
+		"unchanged.txt": StatusUnchanged,
+		"updated.txt":   StatusUpdated,
+		"user.txt":      StatusConflict,
+		"merged.txt":    StatusConflict,
+		"conflict.txt":  StatusConflict,
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("%s: got status %s, want %s", name, got[name], status)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(project, "user.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "<<<<<<< current\nedited\n=======\ngenerated\n>>>>>>> new generation\n") {
+		t.Errorf("user.txt: got %q", content)
+	}
+}
+
+func TestMergeDryRun(t *testing.T) {
+	project, regenerated, previous := setupProject(t)
+	before := readTree(t, project)
+
+	result, err := Merge(project, previous, regenerated, &Options{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.Conflicts(); !reflect.DeepEqual(got, []string{"conflict.txt"}) {
+		t.Errorf("got conflicts %v", got)
+	}
+	if after := readTree(t, project); !reflect.DeepEqual(after, before) {
+		t.Errorf("project changed:\n%v\nwant:\n%v", after, before)
+	}
+}
+
+func TestMergeFiles(t *testing.T) {
+	project, regenerated, previous := setupProject(t)
+	before := readTree(t, project)
+
+	result, err := Merge(project, previous, regenerated, &Options{
+		Files: []string{"updated.txt", "obsolete.txt"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantStatuses := map[string]Status{
+		"updated.txt":  StatusUpdated,
+		"obsolete.txt": StatusObsolete,
+	}
+	if got := statuses(result); !reflect.DeepEqual(got, wantStatuses) {
+		t.Errorf("got statuses %v, want %v", got, wantStatuses)
+	}
+
+	// Only the selected files, and their manifest entries, change.
+	tree := readTree(t, project)
+	for name, content := range before {
+		switch name {
+		case "updated.txt", manifest.Filename, manifest.BaseDir + "/updated.txt", manifest.BaseDir + "/obsolete.txt":
+			continue
+		}
+		if tree[name] != content {
+			t.Errorf("%s: got %q, want %q", name, tree[name], content)
+		}
+	}
+	if _, ok := tree["dir/added.txt"]; ok {
+		t.Error("dir/added.txt: generated")
+	}
+
+	m, err := manifest.Load(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Answers, previous.Answers) || !m.GeneratedAt.Equal(previous.GeneratedAt) {
+		t.Error("manifest: replaced by the regenerated one")
+	}
+	if _, ok := m.File("obsolete.txt"); ok {
+		t.Error("manifest: obsolete.txt still recorded")
+	}
+	if _, ok := m.File("dir/added.txt"); ok {
+		t.Error("manifest: dir/added.txt recorded")
+	}
+	wantBases := map[string]string{
+		"updated.txt": regeneratedFiles["updated.txt"],
+		"merged.txt":  previousFiles["merged.txt"],
+	}
+	for name, want := range wantBases {
+		f, ok := m.File(name)
+		if !ok {
+			t.Fatalf("manifest: %s not recorded", name)
+		}
+		if base, ok := f.Base(project); !ok || string(base) != want {
+			t.Errorf("base of %s: got %q (%v)", name, base, ok)
+		}
+	}
+}
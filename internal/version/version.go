@@ -0,0 +1,24 @@
+package version
+
+import (
+	"runtime/debug"
+)
+
+// version can be set at build time with:
+//
+//	-ldflags "-X github.com/mikros-dev/mikros-cli/internal/version.version=v1.0.0"
+var version string
+
+// Get returns the CLI version. When not set at build time, the module version
+// from the build information is used.
+func Get() string {
+	if version != "" {
+		return version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	return "(devel)"
+}
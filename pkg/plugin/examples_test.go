@@ -0,0 +1,30 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikros-dev/mikros-cli/internal/golden"
+)
+
+// TestExamples makes sure all plugin examples still build with the SDK.
+func TestExamples(t *testing.T) {
+	for _, kind := range []string{"features", "services"} {
+		basePath := filepath.Join("..", "..", "examples", kind)
+		entries, err := os.ReadDir(basePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			t.Run(kind+"/"+entry.Name(), func(t *testing.T) {
+				golden.BuildPlugin(t, filepath.Join(basePath, entry.Name()), filepath.Join(t.TempDir(), entry.Name()))
+			})
+		}
+	}
+}
@@ -53,6 +53,7 @@ func (f *Feature) Run() error {
 	uFlag := flag.Bool("u", false, "Get UI name")
 	sFlag := flag.Bool("s", false, "Retrieve feature survey questions")
 	vFlag := flag.Bool("v", false, "Validate answers")
+	versionFlag := flag.Bool("V", false, "Get plugin version")
 	input := flag.String("i", "", "Input values for plugin arguments")
 	flag.Parse()
 
@@ -81,6 +82,8 @@ func (f *Feature) Run() error {
 		}
 
 		encoder.SetAnswers(data)
+	case *versionFlag:
+		encoder.SetVersion(pluginVersion(f.api))
 	default:
 		return errors.New("no valid command specified")
 	}
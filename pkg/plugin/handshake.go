@@ -0,0 +1,22 @@
+package plugin
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/mikros-dev/mikros-cli/internal/plugin/wire"
+)
+
+// TemplateAPIVersion returns the version of the helper functions that the
+// CLI executing the plugin makes available to templates. It returns 0 when
+// the CLI does not advertise it, meaning that only the original functions
+// (toCamel, toSnake, toUpperSnake, toKebab, basename and templateName) can
+// be used.
+func TemplateAPIVersion() int {
+	version, err := strconv.Atoi(os.Getenv(wire.TemplateAPIVersionEnv))
+	if err != nil {
+		return 0
+	}
+
+	return version
+}
@@ -53,6 +53,7 @@ func (s *Service) Run() error {
 	vFlag := flag.Bool("v", false, "Validate answers")
 	tFlag := flag.Bool("t", false, "Retrieve plugin custom templates")
 	kFlag := flag.Bool("k", false, "Get service kind")
+	versionFlag := flag.Bool("V", false, "Get plugin version")
 	input := flag.String("i", "", "Input values for plugin arguments")
 	flag.Parse()
 
@@ -89,6 +90,8 @@ func (s *Service) Run() error {
 		encoder.SetTemplate(s.api.Template(in))
 	case *kFlag:
 		encoder.SetKind(s.api.Kind())
+	case *versionFlag:
+		encoder.SetVersion(pluginVersion(s.api))
 	default:
 		return errors.New("no valid command specified")
 	}
@@ -91,8 +91,13 @@ type Template struct {
 // File represents a file structure with customizable content, name, output path,
 // extension, and additional context.
 type File struct {
-	Content   string `json:"content,omitempty"`
-	Name      string `json:"name,omitempty"`
+	Content string `json:"content,omitempty"`
+	Name    string `json:"name,omitempty"`
+
+	// Output is the generated file path, relative to the service directory,
+	// like internal/consumer/handler. Missing directories are created and
+	// template expressions can be used, but the path can't point outside
+	// the service directory.
 	Output    string `json:"output,omitempty"`
 	Extension string `json:"extension,omitempty"`
 
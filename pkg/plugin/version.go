@@ -0,0 +1,25 @@
+package plugin
+
+import (
+	"runtime/debug"
+)
+
+// VersionAPI is an optional API that service and feature plugins can
+// implement to report their versions, which are recorded by mikros CLI in
+// the manifest of generated projects. Plugins not implementing it report
+// their module version, from their build information.
+type VersionAPI interface {
+	Version() string
+}
+
+func pluginVersion(api interface{}) string {
+	if v, ok := api.(VersionAPI); ok {
+		return v.Version()
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Version
+	}
+
+	return ""
+}